@@ -1,5 +1,12 @@
 package gtp5gnl
 
+import (
+	"errors"
+	"net"
+
+	"github.com/khirono/go-nl"
+)
+
 const (
 	ATTR_MAX = 0x10
 )
@@ -8,3 +15,123 @@ const (
 	LINK = iota + 1
 	NET_NS_FD
 )
+
+// RawAttr is an attribute a decoder does not know, kept as received so that
+// data from a newer gtp5g module is not lost. Type has the NLA_F_NESTED and
+// NLA_F_NET_BYTEORDER flags masked off and Value is the payload without the
+// header. The Encode functions do not send Unknown attributes back.
+type RawAttr struct {
+	Type  uint16
+	Value []byte
+}
+
+// rawAttr copies the payload b of the attribute with header hdr.
+func rawAttr(hdr nl.AttrHdr, b []byte) RawAttr {
+	v := make([]byte, len(b))
+	copy(v, b)
+	return RawAttr{Type: uint16(hdr.MaskedType()), Value: v}
+}
+
+// attrSize is the name of an attribute and the minimum size of its payload.
+// The payload of a list attribute is a sequence of size-byte elements.
+type attrSize struct {
+	name string
+	size int
+	list bool
+}
+
+// attrSpec describes the attributes nested in the attribute name, so that
+// a decoder can check them before reading their payload.
+type attrSpec struct {
+	name  string
+	attrs map[int]attrSize
+}
+
+// decodeAttrHdr decodes the header of the first attribute of b like
+// nl.DecodeAttrHdr, and returns a *DecodeError if the attribute does not
+// fit in b or its payload is too short. After it succeeds, b[n:hdr.Len] is
+// the payload.
+func (s attrSpec) decodeAttrHdr(b []byte) (nl.AttrHdr, int, error) {
+	hdr, n, err := nl.DecodeAttrHdr(b)
+	if err != nil {
+		return hdr, n, &DecodeError{Path: s.name, Len: len(b), Want: 4}
+	}
+	a, ok := s.attrs[hdr.MaskedType()]
+	path := s.name
+	if ok {
+		path = joinPath(s.name, a.name)
+	}
+	switch {
+	case int(hdr.Len) < n:
+		return hdr, n, &DecodeError{Path: path, Len: int(hdr.Len), Want: n}
+	case int(hdr.Len) > len(b):
+		return hdr, n, &DecodeError{Path: path, Len: len(b), Want: int(hdr.Len)}
+	case !ok:
+		return hdr, n, nil
+	}
+	switch l := int(hdr.Len) - n; {
+	case l < a.size:
+		return hdr, n, &DecodeError{Path: path, Len: l, Want: a.size}
+	case a.list && l%a.size != 0:
+		return hdr, n, &DecodeError{Path: path, Len: l, Want: l + a.size - l%a.size}
+	}
+	return hdr, n, nil
+}
+
+// wrap prefixes the path of err with s.name if err is a *DecodeError from
+// the decoder of a nested attribute.
+func (s attrSpec) wrap(err error) error {
+	var e *DecodeError
+	if errors.As(err, &e) {
+		e.Path = joinPath(s.name, e.Path)
+	}
+	return err
+}
+
+func joinPath(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + "." + name
+}
+
+// nextAttr skips the attribute with header hdr at the start of b. The
+// padding of the last attribute may be missing.
+func nextAttr(b []byte, hdr nl.AttrHdr) []byte {
+	return b[min(hdr.Len.Align(), len(b)):]
+}
+
+// attrList returns the nested attributes of an encoded attribute value, or
+// nil if it is not nested.
+func attrList(v nl.Encoder) nl.AttrList {
+	switch l := v.(type) {
+	case nl.AttrList:
+		return l
+	case *nl.AttrList:
+		return *l
+	default:
+		return nil
+	}
+}
+
+// decodeIP copies an address of size bytes from b, or returns nil if b is
+// too short.
+func decodeIP(b []byte, size int) net.IP {
+	if len(b) < size {
+		return nil
+	}
+	ip := make(net.IP, size)
+	copy(ip, b)
+	return ip
+}
+
+// decodeMask copies an IPv4 netmask from b, or returns nil if b is too
+// short.
+func decodeMask(b []byte) net.IPMask {
+	if len(b) < net.IPv4len {
+		return nil
+	}
+	m := make(net.IPMask, net.IPv4len)
+	copy(m, b)
+	return m
+}
@@ -12,16 +12,27 @@ const (
 )
 
 type BAR struct {
-	ID    uint8
-	Delay *uint8
-	Count *uint16
-	SEID  *uint64
+	ID      uint8
+	Delay   *uint8
+	Count   *uint16
+	SEID    *uint64
+	Unknown []RawAttr `json:",omitempty"`
+}
+
+var barSpec = attrSpec{
+	name: "BAR",
+	attrs: map[int]attrSize{
+		BAR_ID:                               {name: "ID", size: 1},
+		BAR_DOWNLINK_DATA_NOTIFICATION_DELAY: {name: "DOWNLINK_DATA_NOTIFICATION_DELAY", size: 1},
+		BAR_BUFFERING_PACKETS_COUNT:          {name: "BUFFERING_PACKETS_COUNT", size: 2},
+		BAR_SEID:                             {name: "SEID", size: 8},
+	},
 }
 
 func DecodeBAR(b []byte) (*BAR, error) {
 	bar := new(BAR)
 	for len(b) > 0 {
-		hdr, n, err := nl.DecodeAttrHdr(b)
+		hdr, n, err := barSpec.decodeAttrHdr(b)
 		if err != nil {
 			return nil, err
 		}
@@ -38,8 +49,29 @@ func DecodeBAR(b []byte) (*BAR, error) {
 		case BAR_SEID:
 			v := native.Uint64(b[n:attrLen])
 			bar.SEID = &v
+		default:
+			bar.Unknown = append(bar.Unknown, rawAttr(hdr, b[n:attrLen]))
 		}
-		b = b[hdr.Len.Align():]
+		b = nextAttr(b, hdr)
 	}
 	return bar, nil
 }
+
+// EncodeBAR returns the attributes of bar for CreateBAROID and UpdateBAROID.
+// BAR_ID and BAR_SEID are not included; they are carried by the OID.
+func EncodeBAR(bar *BAR) []nl.Attr {
+	var attrs []nl.Attr
+	if bar.Delay != nil {
+		attrs = append(attrs, nl.Attr{
+			Type:  BAR_DOWNLINK_DATA_NOTIFICATION_DELAY,
+			Value: nl.AttrU8(*bar.Delay),
+		})
+	}
+	if bar.Count != nil {
+		attrs = append(attrs, nl.Attr{
+			Type:  BAR_BUFFERING_PACKETS_COUNT,
+			Value: nl.AttrU16(*bar.Count),
+		})
+	}
+	return attrs
+}
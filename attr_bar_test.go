@@ -0,0 +1,59 @@
+package gtp5gnl
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/khirono/go-nl"
+)
+
+func TestEncodeBAR(t *testing.T) {
+	seid := uint64(0x1234)
+	delay := uint8(2)
+	count := uint16(3)
+
+	cases := []struct {
+		name string
+		bar  BAR
+	}{
+		{
+			name: "empty",
+			bar: BAR{
+				ID: 1,
+			},
+		},
+		{
+			name: "buffering",
+			bar: BAR{
+				ID:    2,
+				Delay: &delay,
+				Count: &count,
+				SEID:  &seid,
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			attrs := []nl.Attr{
+				{
+					Type:  BAR_ID,
+					Value: nl.AttrU8(tc.bar.ID),
+				},
+			}
+			if tc.bar.SEID != nil {
+				attrs = append(attrs, nl.Attr{
+					Type:  BAR_SEID,
+					Value: nl.AttrU64(*tc.bar.SEID),
+				})
+			}
+			attrs = append(attrs, EncodeBAR(&tc.bar)...)
+			bar, err := DecodeBAR(encodeAttrs(t, attrs))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(bar, &tc.bar) {
+				t.Errorf("want %+v; but got %+v\n", tc.bar, *bar)
+			}
+		})
+	}
+}
@@ -16,18 +16,92 @@ const (
 )
 
 type FAR struct {
-	ID     uint32
-	Action uint16
-	Param  *ForwardParam
-	PDRIDs []uint16
-	BARID  *uint8
-	SEID   *uint64
+	ID      uint32
+	Action  ApplyAction
+	Param   *ForwardParam
+	PDRIDs  []uint16
+	BARID   *uint8
+	SEID    *uint64
+	Unknown []RawAttr `json:",omitempty"`
+}
+
+// ApplyAction is the Apply Action IE (TS 29.244 8.2.26).
+type ApplyAction uint16
+
+const (
+	APPLY_ACTION_DROP ApplyAction = 1 << iota // Drop
+	APPLY_ACTION_FORW                         // Forward
+	APPLY_ACTION_BUFF                         // Buffer
+	APPLY_ACTION_NOCP                         // Notify the CP function
+	APPLY_ACTION_DUPL                         // Duplicate
+	APPLY_ACTION_IPMA                         // IP Multicast Accept
+	APPLY_ACTION_IPMD                         // IP Multicast Deny
+	APPLY_ACTION_DFRT                         // Duplicate for Redundant Transmission
+	APPLY_ACTION_EDRT                         // Eliminate Duplicate Packets for Redundant Transmission
+	APPLY_ACTION_BDPN                         // Buffered Downlink Packet Notification
+	APPLY_ACTION_DDPN                         // Discarded Downlink Packet Notification
+)
+
+var applyActionNames = []flagName[ApplyAction]{
+	{APPLY_ACTION_DROP, "DROP"},
+	{APPLY_ACTION_FORW, "FORW"},
+	{APPLY_ACTION_BUFF, "BUFF"},
+	{APPLY_ACTION_NOCP, "NOCP"},
+	{APPLY_ACTION_DUPL, "DUPL"},
+	{APPLY_ACTION_IPMA, "IPMA"},
+	{APPLY_ACTION_IPMD, "IPMD"},
+	{APPLY_ACTION_DFRT, "DFRT"},
+	{APPLY_ACTION_EDRT, "EDRT"},
+	{APPLY_ACTION_BDPN, "BDPN"},
+	{APPLY_ACTION_DDPN, "DDPN"},
+}
+
+func (a ApplyAction) Has(flag ApplyAction) bool {
+	return a&flag == flag
+}
+
+// String returns the set flags joined by "|", e.g. "BUFF|NOCP".
+func (a ApplyAction) String() string {
+	return formatFlags(a, applyActionNames)
+}
+
+// ParseApplyAction parses a number or flag names separated by "|" or ",",
+// e.g. "FORW" or "buff,nocp".
+func ParseApplyAction(s string) (ApplyAction, error) {
+	return parseFlags(s, applyActionNames, "apply action")
+}
+
+// MarshalJSON encodes a as a list of flag names, e.g. ["BUFF","NOCP"].
+func (a ApplyAction) MarshalJSON() ([]byte, error) {
+	return marshalFlags(a, applyActionNames)
+}
+
+// UnmarshalJSON accepts a list of flag names or a number.
+func (a *ApplyAction) UnmarshalJSON(b []byte) error {
+	v, err := unmarshalFlags(b, applyActionNames, "apply action")
+	if err != nil {
+		return err
+	}
+	*a = v
+	return nil
+}
+
+var farSpec = attrSpec{
+	name: "FAR",
+	attrs: map[int]attrSize{
+		FAR_ID:                   {name: "ID", size: 4},
+		FAR_APPLY_ACTION:         {name: "APPLY_ACTION", size: 2},
+		FAR_FORWARDING_PARAMETER: {name: "FORWARDING_PARAMETER"},
+		FAR_RELATED_TO_PDR:       {name: "RELATED_TO_PDR", size: 2, list: true},
+		FAR_SEID:                 {name: "SEID", size: 8},
+		FAR_BAR_ID:               {name: "BAR_ID", size: 1},
+	},
 }
 
 func DecodeFAR(b []byte) (*FAR, error) {
 	far := new(FAR)
 	for len(b) > 0 {
-		hdr, n, err := nl.DecodeAttrHdr(b)
+		hdr, n, err := farSpec.decodeAttrHdr(b)
 		if err != nil {
 			return nil, err
 		}
@@ -36,11 +110,11 @@ func DecodeFAR(b []byte) (*FAR, error) {
 		case FAR_ID:
 			far.ID = native.Uint32(b[n:attrLen])
 		case FAR_APPLY_ACTION:
-			far.Action = native.Uint16(b[n:attrLen])
+			far.Action = ApplyAction(native.Uint16(b[n:attrLen]))
 		case FAR_FORWARDING_PARAMETER:
 			param, err := DecodeForwardParam(b[n:attrLen])
 			if err != nil {
-				return nil, err
+				return nil, farSpec.wrap(err)
 			}
 			far.Param = &param
 		case FAR_RELATED_TO_PDR:
@@ -56,12 +130,39 @@ func DecodeFAR(b []byte) (*FAR, error) {
 		case FAR_SEID:
 			v := native.Uint64(b[n:attrLen])
 			far.SEID = &v
+		default:
+			far.Unknown = append(far.Unknown, rawAttr(hdr, b[n:attrLen]))
 		}
-		b = b[hdr.Len.Align():]
+		b = nextAttr(b, hdr)
 	}
 	return far, nil
 }
 
+// EncodeFAR returns the attributes of far for CreateFAROID and UpdateFAROID.
+// FAR_ID and FAR_SEID are not included; they are carried by the OID.
+// PDRIDs is reported by the kernel and is not encoded.
+func EncodeFAR(far *FAR) []nl.Attr {
+	attrs := []nl.Attr{
+		{
+			Type:  FAR_APPLY_ACTION,
+			Value: nl.AttrU16(far.Action),
+		},
+	}
+	if far.Param != nil {
+		attrs = append(attrs, nl.Attr{
+			Type:  FAR_FORWARDING_PARAMETER,
+			Value: nl.AttrList(EncodeForwardParam(*far.Param)),
+		})
+	}
+	if far.BARID != nil {
+		attrs = append(attrs, nl.Attr{
+			Type:  FAR_BAR_ID,
+			Value: nl.AttrU8(*far.BARID),
+		})
+	}
+	return attrs
+}
+
 const (
 	FORWARDING_PARAMETER_OUTER_HEADER_CREATION = iota + 1
 	FORWARDING_PARAMETER_FORWARDING_POLICY
@@ -70,15 +171,59 @@ const (
 )
 
 type ForwardParam struct {
-	Creation *HeaderCreation
-	Policy   *string
-	TosTc    uint8
+	Creation       *HeaderCreation
+	Policy         *string
+	PFCPSMReqFlags PFCPSMReqFlags
+	// TosTc is sent only if non-zero, so that an update does not reset
+	// the value of the kernel.
+	TosTc   uint8
+	Unknown []RawAttr `json:",omitempty"`
+}
+
+// PFCPSMReqFlags is the PFCPSMReq-Flags IE (TS 29.244 8.2.58).
+type PFCPSMReqFlags uint8
+
+const (
+	PFCPSM_REQ_FLAGS_DROBU PFCPSMReqFlags = 1 << iota // Drop Buffered Packets
+	PFCPSM_REQ_FLAGS_SNDEM                            // Send End Marker Packets
+	PFCPSM_REQ_FLAGS_QAURR                            // Query All URRs
+)
+
+var pfcpsmReqFlagNames = []flagName[PFCPSMReqFlags]{
+	{PFCPSM_REQ_FLAGS_DROBU, "DROBU"},
+	{PFCPSM_REQ_FLAGS_SNDEM, "SNDEM"},
+	{PFCPSM_REQ_FLAGS_QAURR, "QAURR"},
+}
+
+func (f PFCPSMReqFlags) Has(flag PFCPSMReqFlags) bool {
+	return f&flag == flag
+}
+
+// String returns the set flags joined by "|", e.g. "DROBU|SNDEM".
+func (f PFCPSMReqFlags) String() string {
+	return formatFlags(f, pfcpsmReqFlagNames)
+}
+
+// ParsePFCPSMReqFlags parses a number or flag names separated by "|" or
+// ",", e.g. "SNDEM" or "drobu,sndem".
+func ParsePFCPSMReqFlags(s string) (PFCPSMReqFlags, error) {
+	return parseFlags(s, pfcpsmReqFlagNames, "PFCPSMReq")
+}
+
+var forwardParamSpec = attrSpec{
+	name: "FORWARDING_PARAMETER",
+	attrs: map[int]attrSize{
+		FORWARDING_PARAMETER_OUTER_HEADER_CREATION: {name: "OUTER_HEADER_CREATION"},
+		FORWARDING_PARAMETER_FORWARDING_POLICY:     {name: "FORWARDING_POLICY"},
+		FORWARDING_PARAMETER_PFCPSM_REQ_FLAGS:      {name: "PFCPSM_REQ_FLAGS", size: 1},
+		FORWARDING_PARAMETER_TOS_TC:                {name: "TOS_TC", size: 1},
+	},
 }
 
 func DecodeForwardParam(b []byte) (ForwardParam, error) {
 	var param ForwardParam
 	for len(b) > 0 {
-		hdr, n, err := nl.DecodeAttrHdr(b)
+		hdr, n, err := forwardParamSpec.decodeAttrHdr(b)
 		if err != nil {
 			return param, err
 		}
@@ -87,20 +232,53 @@ func DecodeForwardParam(b []byte) (ForwardParam, error) {
 		case FORWARDING_PARAMETER_OUTER_HEADER_CREATION:
 			hc, err := DecodeHeaderCreation(b[n:attrLen])
 			if err != nil {
-				return param, err
+				return param, forwardParamSpec.wrap(err)
 			}
 			param.Creation = &hc
 		case FORWARDING_PARAMETER_FORWARDING_POLICY:
 			s, _, _ := nl.DecodeAttrString(b[n:attrLen])
 			param.Policy = &s
+		case FORWARDING_PARAMETER_PFCPSM_REQ_FLAGS:
+			param.PFCPSMReqFlags = PFCPSMReqFlags(b[n])
 		case FORWARDING_PARAMETER_TOS_TC:
 			param.TosTc = b[n]
+		default:
+			param.Unknown = append(param.Unknown, rawAttr(hdr, b[n:attrLen]))
 		}
-		b = b[hdr.Len.Align():]
+		b = nextAttr(b, hdr)
 	}
 	return param, nil
 }
 
+func EncodeForwardParam(param ForwardParam) []nl.Attr {
+	var attrs []nl.Attr
+	if param.Creation != nil {
+		attrs = append(attrs, nl.Attr{
+			Type:  FORWARDING_PARAMETER_OUTER_HEADER_CREATION,
+			Value: nl.AttrList(EncodeHeaderCreation(*param.Creation)),
+		})
+	}
+	if param.Policy != nil {
+		attrs = append(attrs, nl.Attr{
+			Type:  FORWARDING_PARAMETER_FORWARDING_POLICY,
+			Value: nl.AttrString(*param.Policy),
+		})
+	}
+	if param.PFCPSMReqFlags != 0 {
+		attrs = append(attrs, nl.Attr{
+			Type:  FORWARDING_PARAMETER_PFCPSM_REQ_FLAGS,
+			Value: nl.AttrU8(param.PFCPSMReqFlags),
+		})
+	}
+	if param.TosTc != 0 {
+		attrs = append(attrs, nl.Attr{
+			Type:  FORWARDING_PARAMETER_TOS_TC,
+			Value: nl.AttrU8(param.TosTc),
+		})
+	}
+	return attrs
+}
+
 const (
 	OUTER_HEADER_CREATION_DESCRIPTION = iota + 1
 	OUTER_HEADER_CREATION_O_TEID
@@ -113,12 +291,23 @@ type HeaderCreation struct {
 	TEID     uint32
 	PeerAddr net.IP
 	Port     uint16
+	Unknown  []RawAttr `json:",omitempty"`
+}
+
+var headerCreationSpec = attrSpec{
+	name: "OUTER_HEADER_CREATION",
+	attrs: map[int]attrSize{
+		OUTER_HEADER_CREATION_DESCRIPTION:    {name: "DESCRIPTION", size: 2},
+		OUTER_HEADER_CREATION_O_TEID:         {name: "O_TEID", size: 4},
+		OUTER_HEADER_CREATION_PEER_ADDR_IPV4: {name: "PEER_ADDR_IPV4", size: 4},
+		OUTER_HEADER_CREATION_PORT:           {name: "PORT", size: 2},
+	},
 }
 
 func DecodeHeaderCreation(b []byte) (HeaderCreation, error) {
 	var hc HeaderCreation
 	for len(b) > 0 {
-		hdr, n, err := nl.DecodeAttrHdr(b)
+		hdr, n, err := headerCreationSpec.decodeAttrHdr(b)
 		if err != nil {
 			return hc, err
 		}
@@ -129,12 +318,37 @@ func DecodeHeaderCreation(b []byte) (HeaderCreation, error) {
 		case OUTER_HEADER_CREATION_O_TEID:
 			hc.TEID = native.Uint32(b[n:attrLen])
 		case OUTER_HEADER_CREATION_PEER_ADDR_IPV4:
-			hc.PeerAddr = make([]byte, 4)
-			copy(hc.PeerAddr, b[n:n+4])
+			hc.PeerAddr = decodeIP(b[n:attrLen], net.IPv4len)
 		case OUTER_HEADER_CREATION_PORT:
 			hc.Port = native.Uint16(b[n:attrLen])
+		default:
+			hc.Unknown = append(hc.Unknown, rawAttr(hdr, b[n:attrLen]))
 		}
-		b = b[hdr.Len.Align():]
+		b = nextAttr(b, hdr)
 	}
 	return hc, nil
 }
+
+func EncodeHeaderCreation(hc HeaderCreation) []nl.Attr {
+	attrs := []nl.Attr{
+		{
+			Type:  OUTER_HEADER_CREATION_DESCRIPTION,
+			Value: nl.AttrU16(hc.Desc),
+		},
+		{
+			Type:  OUTER_HEADER_CREATION_O_TEID,
+			Value: nl.AttrU32(hc.TEID),
+		},
+	}
+	if hc.PeerAddr != nil {
+		attrs = append(attrs, nl.Attr{
+			Type:  OUTER_HEADER_CREATION_PEER_ADDR_IPV4,
+			Value: nl.AttrBytes(hc.PeerAddr.To4()),
+		})
+	}
+	attrs = append(attrs, nl.Attr{
+		Type:  OUTER_HEADER_CREATION_PORT,
+		Value: nl.AttrU16(hc.Port),
+	})
+	return attrs
+}
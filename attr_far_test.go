@@ -0,0 +1,141 @@
+package gtp5gnl
+
+import (
+	"encoding/json"
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/khirono/go-nl"
+)
+
+func TestEncodeFAR(t *testing.T) {
+	seid := uint64(0x1234)
+	barid := uint8(1)
+	policy := "mark1"
+
+	cases := []struct {
+		name string
+		far  FAR
+	}{
+		{
+			name: "drop",
+			far: FAR{
+				ID:     1,
+				Action: 1,
+			},
+		},
+		{
+			name: "forward",
+			far: FAR{
+				ID:     2,
+				Action: 2,
+				Param: &ForwardParam{
+					Creation: &HeaderCreation{
+						Desc:     0x0100,
+						TEID:     0x78,
+						PeerAddr: net.IP{10, 60, 0, 1},
+						Port:     2152,
+					},
+					Policy:         &policy,
+					PFCPSMReqFlags: PFCPSM_REQ_FLAGS_SNDEM,
+					TosTc:          0x20,
+				},
+				BARID: &barid,
+				SEID:  &seid,
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			attrs := []nl.Attr{
+				{
+					Type:  FAR_ID,
+					Value: nl.AttrU32(tc.far.ID),
+				},
+			}
+			if tc.far.SEID != nil {
+				attrs = append(attrs, nl.Attr{
+					Type:  FAR_SEID,
+					Value: nl.AttrU64(*tc.far.SEID),
+				})
+			}
+			attrs = append(attrs, EncodeFAR(&tc.far)...)
+			far, err := DecodeFAR(encodeAttrs(t, attrs))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(far, &tc.far) {
+				t.Errorf("want %+v; but got %+v\n", tc.far, *far)
+			}
+		})
+	}
+}
+
+func TestPFCPSMReqFlags(t *testing.T) {
+	f := PFCPSM_REQ_FLAGS_DROBU | PFCPSM_REQ_FLAGS_SNDEM
+	if !f.Has(PFCPSM_REQ_FLAGS_SNDEM) || f.Has(PFCPSM_REQ_FLAGS_QAURR) {
+		t.Errorf("unexpected Has results for %v", f)
+	}
+	if f.String() != "DROBU|SNDEM" {
+		t.Errorf("want DROBU|SNDEM; but got %v", f)
+	}
+	for _, s := range []string{"3", "0x03", "DROBU|SNDEM", "sndem,drobu"} {
+		v, err := ParsePFCPSMReqFlags(s)
+		if err != nil {
+			t.Errorf("ParsePFCPSMReqFlags(%q): %v", s, err)
+			continue
+		}
+		if v != f {
+			t.Errorf("ParsePFCPSMReqFlags(%q) = %v; want %v", s, v, f)
+		}
+	}
+	_, err := ParsePFCPSMReqFlags("SNDEM,FOO")
+	if err == nil {
+		t.Error("want error for an unknown flag")
+	}
+}
+
+func TestApplyAction(t *testing.T) {
+	a := APPLY_ACTION_BUFF | APPLY_ACTION_NOCP
+	if a != 12 {
+		t.Errorf("want 12; but got %d", uint16(a))
+	}
+	if a.String() != "BUFF|NOCP" {
+		t.Errorf("want BUFF|NOCP; but got %v", a)
+	}
+	for _, s := range []string{"12", "BUFF|NOCP", "nocp,buff"} {
+		v, err := ParseApplyAction(s)
+		if err != nil {
+			t.Errorf("ParseApplyAction(%q): %v", s, err)
+			continue
+		}
+		if v != a {
+			t.Errorf("ParseApplyAction(%q) = %v; want %v", s, v, a)
+		}
+	}
+
+	j, err := json.Marshal(FAR{ID: 1, Action: a | 0x8000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"ID":1,"Action":["BUFF","NOCP","0x8000"],"Param":null,"PDRIDs":null,"BARID":null,"SEID":null}`
+	if string(j) != want {
+		t.Errorf("want %s; but got %s", want, j)
+	}
+	var far FAR
+	err = json.Unmarshal(j, &far)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if far.Action != a|0x8000 {
+		t.Errorf("want %v; but got %v", a|0x8000, far.Action)
+	}
+	err = json.Unmarshal([]byte(`{"Action":2}`), &far)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if far.Action != APPLY_ACTION_FORW {
+		t.Errorf("want FORW; but got %v", far.Action)
+	}
+}
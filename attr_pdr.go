@@ -1,8 +1,9 @@
 package gtp5gnl
 
 import (
-	"log"
+	"fmt"
 	"net"
+	"strings"
 
 	"github.com/khirono/go-nl"
 )
@@ -27,16 +28,36 @@ type PDR struct {
 	PDI             *PDI
 	OuterHdrRemoval *uint8
 	FARID           *uint32
+	RoleAddr        net.IP
+	UnixSocketPath  *string
 	QERID           []uint32
 	URRID           []uint32
 	SEID            *uint64
 	PDNType         *uint8
+	Unknown         []RawAttr `json:",omitempty"`
+}
+
+var pdrSpec = attrSpec{
+	name: "PDR",
+	attrs: map[int]attrSize{
+		PDR_ID:                   {name: "ID", size: 2},
+		PDR_PRECEDENCE:           {name: "PRECEDENCE", size: 4},
+		PDR_PDI:                  {name: "PDI"},
+		PDR_OUTER_HEADER_REMOVAL: {name: "OUTER_HEADER_REMOVAL", size: 1},
+		PDR_FAR_ID:               {name: "FAR_ID", size: 4},
+		PDR_ROLE_ADDR_IPV4:       {name: "ROLE_ADDR_IPV4", size: 4},
+		PDR_UNIX_SOCKET_PATH:     {name: "UNIX_SOCKET_PATH"},
+		PDR_QER_ID:               {name: "QER_ID", size: 4},
+		PDR_SEID:                 {name: "SEID", size: 8},
+		PDR_URR_ID:               {name: "URR_ID", size: 4},
+		PDR_PDN_TYPE:             {name: "PDN_TYPE", size: 1},
+	},
 }
 
 func DecodePDR(b []byte) (*PDR, error) {
 	pdr := new(PDR)
 	for len(b) > 0 {
-		hdr, n, err := nl.DecodeAttrHdr(b)
+		hdr, n, err := pdrSpec.decodeAttrHdr(b)
 		if err != nil {
 			return nil, err
 		}
@@ -50,7 +71,7 @@ func DecodePDR(b []byte) (*PDR, error) {
 		case PDR_PDI:
 			pdi, err := DecodePDI(b[n:attrLen])
 			if err != nil {
-				return nil, err
+				return nil, pdrSpec.wrap(err)
 			}
 			pdr.PDI = &pdi
 		case PDR_OUTER_HEADER_REMOVAL:
@@ -59,6 +80,11 @@ func DecodePDR(b []byte) (*PDR, error) {
 		case PDR_FAR_ID:
 			v := native.Uint32(b[n:attrLen])
 			pdr.FARID = &v
+		case PDR_ROLE_ADDR_IPV4:
+			pdr.RoleAddr = decodeIP(b[n:attrLen], net.IPv4len)
+		case PDR_UNIX_SOCKET_PATH:
+			s, _, _ := nl.DecodeAttrString(b[n:attrLen])
+			pdr.UnixSocketPath = &s
 		case PDR_QER_ID:
 			v := native.Uint32(b[n:attrLen])
 			pdr.QERID = append(pdr.QERID, v)
@@ -72,13 +98,79 @@ func DecodePDR(b []byte) (*PDR, error) {
 			v := b[n]
 			pdr.PDNType = &v
 		default:
-			log.Printf("unknown type: %v\n", hdr.Type)
+			pdr.Unknown = append(pdr.Unknown, rawAttr(hdr, b[n:attrLen]))
 		}
-		b = b[hdr.Len.Align():]
+		b = nextAttr(b, hdr)
 	}
 	return pdr, nil
 }
 
+// EncodePDR returns the attributes of pdr for CreatePDROID and UpdatePDROID.
+// PDR_ID and PDR_SEID are not included; they are carried by the OID.
+// It fails if a MAC address of an Ethernet packet filter is invalid.
+func EncodePDR(pdr *PDR) ([]nl.Attr, error) {
+	var attrs []nl.Attr
+	if pdr.Precedence != nil {
+		attrs = append(attrs, nl.Attr{
+			Type:  PDR_PRECEDENCE,
+			Value: nl.AttrU32(*pdr.Precedence),
+		})
+	}
+	if pdr.PDI != nil {
+		pdi, err := EncodePDI(*pdr.PDI)
+		if err != nil {
+			return nil, err
+		}
+		attrs = append(attrs, nl.Attr{
+			Type:  PDR_PDI,
+			Value: nl.AttrList(pdi),
+		})
+	}
+	if pdr.OuterHdrRemoval != nil {
+		attrs = append(attrs, nl.Attr{
+			Type:  PDR_OUTER_HEADER_REMOVAL,
+			Value: nl.AttrU8(*pdr.OuterHdrRemoval),
+		})
+	}
+	if pdr.FARID != nil {
+		attrs = append(attrs, nl.Attr{
+			Type:  PDR_FAR_ID,
+			Value: nl.AttrU32(*pdr.FARID),
+		})
+	}
+	if pdr.RoleAddr != nil {
+		attrs = append(attrs, nl.Attr{
+			Type:  PDR_ROLE_ADDR_IPV4,
+			Value: nl.AttrBytes(pdr.RoleAddr.To4()),
+		})
+	}
+	if pdr.UnixSocketPath != nil {
+		attrs = append(attrs, nl.Attr{
+			Type:  PDR_UNIX_SOCKET_PATH,
+			Value: nl.AttrString(*pdr.UnixSocketPath),
+		})
+	}
+	for _, id := range pdr.QERID {
+		attrs = append(attrs, nl.Attr{
+			Type:  PDR_QER_ID,
+			Value: nl.AttrU32(id),
+		})
+	}
+	for _, id := range pdr.URRID {
+		attrs = append(attrs, nl.Attr{
+			Type:  PDR_URR_ID,
+			Value: nl.AttrU32(id),
+		})
+	}
+	if pdr.PDNType != nil {
+		attrs = append(attrs, nl.Attr{
+			Type:  PDR_PDN_TYPE,
+			Value: nl.AttrU8(*pdr.PDNType),
+		})
+	}
+	return attrs, nil
+}
+
 const (
 	PDI_UE_ADDR_IPV4 = iota + 1
 	PDI_F_TEID
@@ -89,52 +181,124 @@ const (
 )
 
 type PDI struct {
-	SrcIntf *uint8
-	UEAddr  net.IP
-	FTEID   *FTEID
-	SDF     *SDFFilter
-	EPFs    []EthPktFilter
+	SrcIntf      *uint8
+	UEAddr       net.IP
+	FTEID        *FTEID
+	SDF          *SDFFilter
+	EPFs         []EthPktFilter
+	FramedRoutes []net.IPNet
+	Unknown      []RawAttr `json:",omitempty"`
+}
+
+var pdiSpec = attrSpec{
+	name: "PDI",
+	attrs: map[int]attrSize{
+		PDI_UE_ADDR_IPV4:           {name: "UE_ADDR_IPV4", size: 4},
+		PDI_F_TEID:                 {name: "F_TEID"},
+		PDI_SDF_FILTER:             {name: "SDF_FILTER"},
+		PDI_SRC_INTF:               {name: "SRC_INTF", size: 1},
+		PDI_ETHERNET_PACKET_FILTER: {name: "ETHERNET_PACKET_FILTER"},
+		PDI_FRAMED_ROUTE:           {name: "FRAMED_ROUTE"},
+	},
 }
 
 func DecodePDI(b []byte) (PDI, error) {
 	var pdi PDI
 	for len(b) > 0 {
-		hdr, n, err := nl.DecodeAttrHdr(b)
+		hdr, n, err := pdiSpec.decodeAttrHdr(b)
 		if err != nil {
 			return pdi, err
 		}
 		attrLen := int(hdr.Len)
 		switch hdr.MaskedType() {
 		case PDI_UE_ADDR_IPV4:
-			pdi.UEAddr = make([]byte, 4)
-			copy(pdi.UEAddr, b[n:n+4])
+			pdi.UEAddr = decodeIP(b[n:attrLen], net.IPv4len)
 		case PDI_F_TEID:
 			fteid, err := DecodeFTEID(b[n:attrLen])
 			if err != nil {
-				return pdi, err
+				return pdi, pdiSpec.wrap(err)
 			}
 			pdi.FTEID = &fteid
 		case PDI_SDF_FILTER:
 			sdf, err := DecodeSDFFilter(b[n:attrLen])
 			if err != nil {
-				return pdi, err
+				return pdi, pdiSpec.wrap(err)
 			}
 			pdi.SDF = &sdf
 		case PDI_ETHERNET_PACKET_FILTER:
 			epf, err := DecodeEthPktFilter(b[n:attrLen])
 			if err != nil {
-				return pdi, err
+				return pdi, pdiSpec.wrap(err)
 			}
 			pdi.EPFs = append(pdi.EPFs, epf)
 		case PDI_SRC_INTF:
 			v := b[n]
 			pdi.SrcIntf = &v
+		case PDI_FRAMED_ROUTE:
+			// "<cidr> [<gateway> <metric>...]" as in RADIUS Framed-Route
+			v, _, _ := nl.DecodeAttrString(b[n:attrLen])
+			route := strings.Fields(v)
+			if len(route) == 0 {
+				break
+			}
+			_, ipnet, err := net.ParseCIDR(route[0])
+			if err != nil {
+				return pdi, &DecodeError{Path: "PDI.FRAMED_ROUTE", Err: err}
+			}
+			pdi.FramedRoutes = append(pdi.FramedRoutes, *ipnet)
+		default:
+			pdi.Unknown = append(pdi.Unknown, rawAttr(hdr, b[n:attrLen]))
 		}
-		b = b[hdr.Len.Align():]
+		b = nextAttr(b, hdr)
 	}
 	return pdi, nil
 }
 
+func EncodePDI(pdi PDI) ([]nl.Attr, error) {
+	var attrs []nl.Attr
+	if pdi.SrcIntf != nil {
+		attrs = append(attrs, nl.Attr{
+			Type:  PDI_SRC_INTF,
+			Value: nl.AttrU8(*pdi.SrcIntf),
+		})
+	}
+	if pdi.UEAddr != nil {
+		attrs = append(attrs, nl.Attr{
+			Type:  PDI_UE_ADDR_IPV4,
+			Value: nl.AttrBytes(pdi.UEAddr.To4()),
+		})
+	}
+	if pdi.FTEID != nil {
+		attrs = append(attrs, nl.Attr{
+			Type:  PDI_F_TEID,
+			Value: nl.AttrList(EncodeFTEID(*pdi.FTEID)),
+		})
+	}
+	if pdi.SDF != nil {
+		attrs = append(attrs, nl.Attr{
+			Type:  PDI_SDF_FILTER,
+			Value: nl.AttrList(EncodeSDFFilter(*pdi.SDF)),
+		})
+	}
+	for _, epf := range pdi.EPFs {
+		v, err := EncodeEthPktFilter(epf)
+		if err != nil {
+			return nil, err
+		}
+		attrs = append(attrs, nl.Attr{
+			Type:  PDI_ETHERNET_PACKET_FILTER,
+			Value: nl.AttrList(v),
+		})
+	}
+	for _, route := range pdi.FramedRoutes {
+		attrs = append(attrs, nl.Attr{
+			Type:  PDI_FRAMED_ROUTE,
+			Value: nl.AttrString(route.String()),
+		})
+	}
+	return attrs, nil
+}
+
 const (
 	F_TEID_I_TEID = iota + 1
 	F_TEID_GTPU_ADDR_IPV4
@@ -143,12 +307,21 @@ const (
 type FTEID struct {
 	TEID     uint32
 	GTPuAddr net.IP
+	Unknown  []RawAttr `json:",omitempty"`
+}
+
+var fteidSpec = attrSpec{
+	name: "F_TEID",
+	attrs: map[int]attrSize{
+		F_TEID_I_TEID:         {name: "I_TEID", size: 4},
+		F_TEID_GTPU_ADDR_IPV4: {name: "GTPU_ADDR_IPV4", size: 4},
+	},
 }
 
 func DecodeFTEID(b []byte) (FTEID, error) {
 	var fteid FTEID
 	for len(b) > 0 {
-		hdr, n, err := nl.DecodeAttrHdr(b)
+		hdr, n, err := fteidSpec.decodeAttrHdr(b)
 		if err != nil {
 			return fteid, err
 		}
@@ -157,14 +330,31 @@ func DecodeFTEID(b []byte) (FTEID, error) {
 		case F_TEID_I_TEID:
 			fteid.TEID = native.Uint32(b[n:attrLen])
 		case F_TEID_GTPU_ADDR_IPV4:
-			fteid.GTPuAddr = make([]byte, 4)
-			copy(fteid.GTPuAddr, b[n:n+4])
+			fteid.GTPuAddr = decodeIP(b[n:attrLen], net.IPv4len)
+		default:
+			fteid.Unknown = append(fteid.Unknown, rawAttr(hdr, b[n:attrLen]))
 		}
-		b = b[hdr.Len.Align():]
+		b = nextAttr(b, hdr)
 	}
 	return fteid, nil
 }
 
+func EncodeFTEID(fteid FTEID) []nl.Attr {
+	attrs := []nl.Attr{
+		{
+			Type:  F_TEID_I_TEID,
+			Value: nl.AttrU32(fteid.TEID),
+		},
+	}
+	if fteid.GTPuAddr != nil {
+		attrs = append(attrs, nl.Attr{
+			Type:  F_TEID_GTPU_ADDR_IPV4,
+			Value: nl.AttrBytes(fteid.GTPuAddr.To4()),
+		})
+	}
+	return attrs
+}
+
 const (
 	SDF_FILTER_FLOW_DESCRIPTION = iota + 1
 	SDF_FILTER_TOS_TRAFFIC_CLASS
@@ -174,17 +364,29 @@ const (
 )
 
 type SDFFilter struct {
-	FD  *FlowDesc
-	TTC *uint16
-	SPI *uint32
-	FL  *uint32
-	BID *uint32
+	FD      *FlowDesc
+	TTC     *uint16
+	SPI     *uint32
+	FL      *uint32
+	BID     *uint32
+	Unknown []RawAttr `json:",omitempty"`
+}
+
+var sdfFilterSpec = attrSpec{
+	name: "SDF_FILTER",
+	attrs: map[int]attrSize{
+		SDF_FILTER_FLOW_DESCRIPTION:         {name: "FLOW_DESCRIPTION"},
+		SDF_FILTER_TOS_TRAFFIC_CLASS:        {name: "TOS_TRAFFIC_CLASS", size: 2},
+		SDF_FILTER_SECURITY_PARAMETER_INDEX: {name: "SECURITY_PARAMETER_INDEX", size: 4},
+		SDF_FILTER_FLOW_LABEL:               {name: "FLOW_LABEL", size: 4},
+		SDF_FILTER_SDF_FILTER_ID:            {name: "SDF_FILTER_ID", size: 4},
+	},
 }
 
 func DecodeSDFFilter(b []byte) (SDFFilter, error) {
 	var sdf SDFFilter
 	for len(b) > 0 {
-		hdr, n, err := nl.DecodeAttrHdr(b)
+		hdr, n, err := sdfFilterSpec.decodeAttrHdr(b)
 		if err != nil {
 			return sdf, err
 		}
@@ -193,7 +395,7 @@ func DecodeSDFFilter(b []byte) (SDFFilter, error) {
 		case SDF_FILTER_FLOW_DESCRIPTION:
 			fd, err := DecodeFlowDesc(b[n:attrLen])
 			if err != nil {
-				return sdf, err
+				return sdf, sdfFilterSpec.wrap(err)
 			}
 			sdf.FD = &fd
 		case SDF_FILTER_TOS_TRAFFIC_CLASS:
@@ -209,13 +411,48 @@ func DecodeSDFFilter(b []byte) (SDFFilter, error) {
 			v := native.Uint32(b[n:attrLen])
 			sdf.BID = &v
 		default:
-			log.Printf("unknown type: %v\n", hdr.Type)
+			sdf.Unknown = append(sdf.Unknown, rawAttr(hdr, b[n:attrLen]))
 		}
-		b = b[hdr.Len.Align():]
+		b = nextAttr(b, hdr)
 	}
 	return sdf, nil
 }
 
+func EncodeSDFFilter(sdf SDFFilter) []nl.Attr {
+	var attrs []nl.Attr
+	if sdf.FD != nil {
+		attrs = append(attrs, nl.Attr{
+			Type:  SDF_FILTER_FLOW_DESCRIPTION,
+			Value: nl.AttrList(EncodeFlowDesc(*sdf.FD)),
+		})
+	}
+	if sdf.TTC != nil {
+		attrs = append(attrs, nl.Attr{
+			Type:  SDF_FILTER_TOS_TRAFFIC_CLASS,
+			Value: nl.AttrU16(*sdf.TTC),
+		})
+	}
+	if sdf.SPI != nil {
+		attrs = append(attrs, nl.Attr{
+			Type:  SDF_FILTER_SECURITY_PARAMETER_INDEX,
+			Value: nl.AttrU32(*sdf.SPI),
+		})
+	}
+	if sdf.FL != nil {
+		attrs = append(attrs, nl.Attr{
+			Type:  SDF_FILTER_FLOW_LABEL,
+			Value: nl.AttrU32(*sdf.FL),
+		})
+	}
+	if sdf.BID != nil {
+		attrs = append(attrs, nl.Attr{
+			Type:  SDF_FILTER_SDF_FILTER_ID,
+			Value: nl.AttrU32(*sdf.BID),
+		})
+	}
+	return attrs
+}
+
 const (
 	FLOW_DESCRIPTION_ACTION = iota + 1
 	FLOW_DESCRIPTION_DIRECTION
@@ -247,12 +484,28 @@ type FlowDesc struct {
 	Dst      net.IPNet
 	SrcPorts [][]uint16
 	DstPorts [][]uint16
+	Unknown  []RawAttr `json:",omitempty"`
+}
+
+var flowDescSpec = attrSpec{
+	name: "FLOW_DESCRIPTION",
+	attrs: map[int]attrSize{
+		FLOW_DESCRIPTION_ACTION:    {name: "ACTION", size: 1},
+		FLOW_DESCRIPTION_DIRECTION: {name: "DIRECTION", size: 1},
+		FLOW_DESCRIPTION_PROTOCOL:  {name: "PROTOCOL", size: 1},
+		FLOW_DESCRIPTION_SRC_IPV4:  {name: "SRC_IPV4", size: 4},
+		FLOW_DESCRIPTION_SRC_MASK:  {name: "SRC_MASK", size: 4},
+		FLOW_DESCRIPTION_DEST_IPV4: {name: "DEST_IPV4", size: 4},
+		FLOW_DESCRIPTION_DEST_MASK: {name: "DEST_MASK", size: 4},
+		FLOW_DESCRIPTION_SRC_PORT:  {name: "SRC_PORT", size: 4, list: true},
+		FLOW_DESCRIPTION_DEST_PORT: {name: "DEST_PORT", size: 4, list: true},
+	},
 }
 
 func DecodeFlowDesc(b []byte) (FlowDesc, error) {
 	var fd FlowDesc
 	for len(b) > 0 {
-		hdr, n, err := nl.DecodeAttrHdr(b)
+		hdr, n, err := flowDescSpec.decodeAttrHdr(b)
 		if err != nil {
 			return fd, err
 		}
@@ -265,17 +518,13 @@ func DecodeFlowDesc(b []byte) (FlowDesc, error) {
 		case FLOW_DESCRIPTION_PROTOCOL:
 			fd.Proto = b[n]
 		case FLOW_DESCRIPTION_SRC_IPV4:
-			fd.Src.IP = make([]byte, 4)
-			copy(fd.Src.IP, b[n:n+4])
+			fd.Src.IP = decodeIP(b[n:attrLen], net.IPv4len)
 		case FLOW_DESCRIPTION_SRC_MASK:
-			fd.Src.Mask = make([]byte, 4)
-			copy(fd.Src.Mask, b[n:n+4])
+			fd.Src.Mask = decodeMask(b[n:attrLen])
 		case FLOW_DESCRIPTION_DEST_IPV4:
-			fd.Dst.IP = make([]byte, 4)
-			copy(fd.Dst.IP, b[n:n+4])
+			fd.Dst.IP = decodeIP(b[n:attrLen], net.IPv4len)
 		case FLOW_DESCRIPTION_DEST_MASK:
-			fd.Dst.Mask = make([]byte, 4)
-			copy(fd.Dst.Mask, b[n:n+4])
+			fd.Dst.Mask = decodeMask(b[n:attrLen])
 		case FLOW_DESCRIPTION_SRC_PORT:
 			for n < attrLen {
 				v := native.Uint32(b[n:attrLen])
@@ -301,13 +550,84 @@ func DecodeFlowDesc(b []byte) (FlowDesc, error) {
 				n += 4
 			}
 		default:
-			log.Printf("unknown type: %v\n", hdr.Type)
+			fd.Unknown = append(fd.Unknown, rawAttr(hdr, b[n:attrLen]))
 		}
-		b = b[hdr.Len.Align():]
+		b = nextAttr(b, hdr)
 	}
 	return fd, nil
 }
 
+func EncodeFlowDesc(fd FlowDesc) []nl.Attr {
+	attrs := []nl.Attr{
+		{
+			Type:  FLOW_DESCRIPTION_ACTION,
+			Value: nl.AttrU8(fd.Action),
+		},
+		{
+			Type:  FLOW_DESCRIPTION_DIRECTION,
+			Value: nl.AttrU8(fd.Dir),
+		},
+		{
+			Type:  FLOW_DESCRIPTION_PROTOCOL,
+			Value: nl.AttrU8(fd.Proto),
+		},
+	}
+	if fd.Src.IP != nil {
+		attrs = append(attrs, nl.Attr{
+			Type:  FLOW_DESCRIPTION_SRC_IPV4,
+			Value: nl.AttrBytes(fd.Src.IP.To4()),
+		})
+	}
+	if fd.Src.Mask != nil {
+		attrs = append(attrs, nl.Attr{
+			Type:  FLOW_DESCRIPTION_SRC_MASK,
+			Value: nl.AttrBytes(fd.Src.Mask),
+		})
+	}
+	if fd.Dst.IP != nil {
+		attrs = append(attrs, nl.Attr{
+			Type:  FLOW_DESCRIPTION_DEST_IPV4,
+			Value: nl.AttrBytes(fd.Dst.IP.To4()),
+		})
+	}
+	if fd.Dst.Mask != nil {
+		attrs = append(attrs, nl.Attr{
+			Type:  FLOW_DESCRIPTION_DEST_MASK,
+			Value: nl.AttrBytes(fd.Dst.Mask),
+		})
+	}
+	if len(fd.SrcPorts) != 0 {
+		attrs = append(attrs, nl.Attr{
+			Type:  FLOW_DESCRIPTION_SRC_PORT,
+			Value: nl.AttrBytes(encodePorts(fd.SrcPorts)),
+		})
+	}
+	if len(fd.DstPorts) != 0 {
+		attrs = append(attrs, nl.Attr{
+			Type:  FLOW_DESCRIPTION_DEST_PORT,
+			Value: nl.AttrBytes(encodePorts(fd.DstPorts)),
+		})
+	}
+	return attrs
+}
+
+// encodePorts packs each port or port range as a u32 of
+// (lower bound << 16 | upper bound).
+func encodePorts(ports [][]uint16) []byte {
+	b := make([]byte, len(ports)*4)
+	off := 0
+	for _, p := range ports {
+		switch len(p) {
+		case 1:
+			native.PutUint32(b[off:], uint32(p[0])<<16|uint32(p[0]))
+		case 2:
+			native.PutUint32(b[off:], uint32(p[0])<<16|uint32(p[1]))
+		}
+		off += 4
+	}
+	return b
+}
+
 const (
 	EPF_FILTER_ETHERNET_FILTER_ID = iota + 1
 	EPF_FILTER_ETHERNET_FILTER_PROPERTIES
@@ -320,8 +640,56 @@ const (
 
 type EthPktFilter struct {
 	EthFilterID *uint32
+	Properties  *uint8
 	MACAddrs    []MACAddrFields
 	Ethertype   *uint16
+	CTAG        *VLANTag
+	STAG        *VLANTag
+	SDFs        []SDFFilter
+	Unknown     []RawAttr `json:",omitempty"`
+}
+
+// Ethernet Filter Properties
+const (
+	EPF_PROPERTIES_BIDE = 1 << iota
+)
+
+// VLAN tag presence flags
+const (
+	VLAN_TAG_PCP = 1 << iota
+	VLAN_TAG_DEI
+	VLAN_TAG_VID
+)
+
+// VLANTag is a C-TAG or S-TAG. Flags tells which of PCP, DEI and VID are
+// set.
+type VLANTag struct {
+	Flags uint8
+	PCP   uint8
+	DEI   uint8
+	VID   uint16
+}
+
+// DecodeVLANTag decodes the 3 octets of a C-TAG or S-TAG as laid out in
+// TS 29.244 8.2.94/8.2.95.
+func DecodeVLANTag(b []byte) VLANTag {
+	var tag VLANTag
+	if len(b) < 3 {
+		return tag
+	}
+	tag.Flags = b[0] & (VLAN_TAG_PCP | VLAN_TAG_DEI | VLAN_TAG_VID)
+	tag.PCP = b[1] & 0x07
+	tag.DEI = (b[1] >> 3) & 0x01
+	tag.VID = uint16(b[1]>>4)<<8 | uint16(b[2])
+	return tag
+}
+
+func EncodeVLANTag(tag VLANTag) []byte {
+	return []byte{
+		tag.Flags & (VLAN_TAG_PCP | VLAN_TAG_DEI | VLAN_TAG_VID),
+		uint8(tag.VID>>8&0x0f)<<4 | (tag.DEI&0x01)<<3 | tag.PCP&0x07,
+		uint8(tag.VID),
+	}
 }
 
 const (
@@ -331,47 +699,113 @@ const (
 	MACADDRESS_UPPER_DST
 )
 
+// MACAddrFields is the MAC address IE (TS 29.244 8.2.93). Empty addresses
+// are not set.
 type MACAddrFields struct {
 	SourceMACAddress           string
 	DestinationMACAddress      string
 	UpperSourceMACAddress      string
 	UpperDestinationMACAddress string
+	Unknown                    []RawAttr `json:",omitempty"`
+}
+
+// ParseMACAddr parses a 48-bit MAC address in any format of net.ParseMAC.
+func ParseMACAddr(s string) (net.HardwareAddr, error) {
+	a, err := net.ParseMAC(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(a) != 6 {
+		return nil, fmt.Errorf("invalid MAC address %q: not 48 bits", s)
+	}
+	return a, nil
+}
+
+var macAddrSpec = attrSpec{
+	name: "MACADDRESS",
+	attrs: map[int]attrSize{
+		MACADDRESS_SRC:       {name: "SRC", size: 6},
+		MACADDRESS_DST:       {name: "DST", size: 6},
+		MACADDRESS_UPPER_SRC: {name: "UPPER_SRC", size: 6},
+		MACADDRESS_UPPER_DST: {name: "UPPER_DST", size: 6},
+	},
 }
 
 func DecodeMACAddrFields(b []byte) (MACAddrFields, error) {
 	var macAddrFields MACAddrFields
-	var macAddr net.HardwareAddr = make([]byte, 6)
 	for len(b) > 0 {
-		hdr, n, err := nl.DecodeAttrHdr(b)
+		hdr, n, err := macAddrSpec.decodeAttrHdr(b)
 		if err != nil {
 			return macAddrFields, err
 		}
 		attrLen := int(hdr.Len)
 		switch hdr.MaskedType() {
 		case MACADDRESS_SRC:
-			copy(macAddr, b[n:attrLen])
-			macAddrFields.SourceMACAddress = macAddr.String()
+			macAddrFields.SourceMACAddress = decodeMACAddr(b[n:attrLen])
 		case MACADDRESS_DST:
-			copy(macAddr, b[n:attrLen])
-			macAddrFields.DestinationMACAddress = macAddr.String()
+			macAddrFields.DestinationMACAddress = decodeMACAddr(b[n:attrLen])
 		case MACADDRESS_UPPER_SRC:
-			copy(macAddr, b[n:attrLen])
-			macAddrFields.UpperSourceMACAddress = macAddr.String()
+			macAddrFields.UpperSourceMACAddress = decodeMACAddr(b[n:attrLen])
 		case MACADDRESS_UPPER_DST:
-			copy(macAddr, b[n:attrLen])
-			macAddrFields.UpperDestinationMACAddress = macAddr.String()
+			macAddrFields.UpperDestinationMACAddress = decodeMACAddr(b[n:attrLen])
 		default:
-			log.Printf("unknown type: %v\n", hdr.Type)
+			macAddrFields.Unknown = append(macAddrFields.Unknown, rawAttr(hdr, b[n:attrLen]))
 		}
-		b = b[hdr.Len.Align():]
+		b = nextAttr(b, hdr)
 	}
 	return macAddrFields, nil
 }
 
+func decodeMACAddr(b []byte) string {
+	return net.HardwareAddr(b[:6]).String()
+}
+
+// EncodeMACAddrFields fails if a MAC address that is set is not a valid
+// 48-bit address.
+func EncodeMACAddrFields(macAddrFields MACAddrFields) ([]nl.Attr, error) {
+	var attrs []nl.Attr
+	fields := []struct {
+		typ  uint16
+		addr string
+	}{
+		{MACADDRESS_SRC, macAddrFields.SourceMACAddress},
+		{MACADDRESS_DST, macAddrFields.DestinationMACAddress},
+		{MACADDRESS_UPPER_SRC, macAddrFields.UpperSourceMACAddress},
+		{MACADDRESS_UPPER_DST, macAddrFields.UpperDestinationMACAddress},
+	}
+	for _, f := range fields {
+		if f.addr == "" {
+			continue
+		}
+		macAddr, err := ParseMACAddr(f.addr)
+		if err != nil {
+			return nil, err
+		}
+		attrs = append(attrs, nl.Attr{
+			Type:  f.typ,
+			Value: nl.AttrBytes(macAddr),
+		})
+	}
+	return attrs, nil
+}
+
+var ethPktFilterSpec = attrSpec{
+	name: "ETHERNET_PACKET_FILTER",
+	attrs: map[int]attrSize{
+		EPF_FILTER_ETHERNET_FILTER_ID:         {name: "ETHERNET_FILTER_ID", size: 4},
+		EPF_FILTER_ETHERNET_FILTER_PROPERTIES: {name: "ETHERNET_FILTER_PROPERTIES", size: 1},
+		EPF_FILTER_MACADDRESS:                 {name: "MACADDRESS"},
+		EPF_FILTER_ETHERTYPE:                  {name: "ETHERTYPE", size: 2},
+		EPF_FILTER_CTAG:                       {name: "CTAG", size: 3},
+		EPF_FILTER_STAG:                       {name: "STAG", size: 3},
+		EPF_FILTER_SDF_FILTER:                 {name: "SDF_FILTER"},
+	},
+}
+
 func DecodeEthPktFilter(b []byte) (EthPktFilter, error) {
 	var epf EthPktFilter
 	for len(b) > 0 {
-		hdr, n, err := nl.DecodeAttrHdr(b)
+		hdr, n, err := ethPktFilterSpec.decodeAttrHdr(b)
 		if err != nil {
 			return epf, err
 		}
@@ -380,19 +814,85 @@ func DecodeEthPktFilter(b []byte) (EthPktFilter, error) {
 		case EPF_FILTER_ETHERNET_FILTER_ID:
 			v := native.Uint32(b[n:attrLen])
 			epf.EthFilterID = &v
+		case EPF_FILTER_ETHERNET_FILTER_PROPERTIES:
+			v := b[n]
+			epf.Properties = &v
 		case EPF_FILTER_MACADDRESS:
 			macAddrFields, err := DecodeMACAddrFields(b[n:attrLen])
 			if err != nil {
-				return epf, err
+				return epf, ethPktFilterSpec.wrap(err)
 			}
 			epf.MACAddrs = append(epf.MACAddrs, macAddrFields)
 		case EPF_FILTER_ETHERTYPE:
 			v := native.Uint16(b[n:attrLen])
 			epf.Ethertype = &v
+		case EPF_FILTER_CTAG:
+			v := DecodeVLANTag(b[n:attrLen])
+			epf.CTAG = &v
+		case EPF_FILTER_STAG:
+			v := DecodeVLANTag(b[n:attrLen])
+			epf.STAG = &v
+		case EPF_FILTER_SDF_FILTER:
+			sdf, err := DecodeSDFFilter(b[n:attrLen])
+			if err != nil {
+				return epf, ethPktFilterSpec.wrap(err)
+			}
+			epf.SDFs = append(epf.SDFs, sdf)
 		default:
-			log.Printf("unknown type: %v\n", hdr.Type)
+			epf.Unknown = append(epf.Unknown, rawAttr(hdr, b[n:attrLen]))
 		}
-		b = b[hdr.Len.Align():]
+		b = nextAttr(b, hdr)
 	}
 	return epf, nil
 }
+
+func EncodeEthPktFilter(epf EthPktFilter) ([]nl.Attr, error) {
+	var attrs []nl.Attr
+	if epf.EthFilterID != nil {
+		attrs = append(attrs, nl.Attr{
+			Type:  EPF_FILTER_ETHERNET_FILTER_ID,
+			Value: nl.AttrU32(*epf.EthFilterID),
+		})
+	}
+	if epf.Properties != nil {
+		attrs = append(attrs, nl.Attr{
+			Type:  EPF_FILTER_ETHERNET_FILTER_PROPERTIES,
+			Value: nl.AttrU8(*epf.Properties),
+		})
+	}
+	for _, macAddrFields := range epf.MACAddrs {
+		v, err := EncodeMACAddrFields(macAddrFields)
+		if err != nil {
+			return nil, err
+		}
+		attrs = append(attrs, nl.Attr{
+			Type:  EPF_FILTER_MACADDRESS,
+			Value: nl.AttrList(v),
+		})
+	}
+	if epf.Ethertype != nil {
+		attrs = append(attrs, nl.Attr{
+			Type:  EPF_FILTER_ETHERTYPE,
+			Value: nl.AttrU16(*epf.Ethertype),
+		})
+	}
+	if epf.CTAG != nil {
+		attrs = append(attrs, nl.Attr{
+			Type:  EPF_FILTER_CTAG,
+			Value: nl.AttrBytes(EncodeVLANTag(*epf.CTAG)),
+		})
+	}
+	if epf.STAG != nil {
+		attrs = append(attrs, nl.Attr{
+			Type:  EPF_FILTER_STAG,
+			Value: nl.AttrBytes(EncodeVLANTag(*epf.STAG)),
+		})
+	}
+	for _, sdf := range epf.SDFs {
+		attrs = append(attrs, nl.Attr{
+			Type:  EPF_FILTER_SDF_FILTER,
+			Value: nl.AttrList(EncodeSDFFilter(sdf)),
+		})
+	}
+	return attrs, nil
+}
@@ -0,0 +1,247 @@
+package gtp5gnl
+
+import (
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/khirono/go-nl"
+)
+
+func TestEncodePDR(t *testing.T) {
+	precedence := uint32(255)
+	hdrRemoval := uint8(0)
+	farid := uint32(2)
+	seid := uint64(0x1234)
+	pdnType := uint8(1)
+	srcIntf := uint8(0)
+	ttc := uint16(0x1f00)
+	spi := uint32(7)
+	fl := uint32(0x12345)
+	bid := uint32(9)
+	epfid := uint32(3)
+	ethertype := uint16(0x0800)
+	epfProps := uint8(EPF_PROPERTIES_BIDE)
+	usock := "/tmp/upf-buffer.sock"
+
+	cases := []struct {
+		name string
+		pdr  PDR
+	}{
+		{
+			name: "empty",
+			pdr: PDR{
+				ID: 1,
+			},
+		},
+		{
+			name: "uplink",
+			pdr: PDR{
+				ID:              1,
+				Precedence:      &precedence,
+				OuterHdrRemoval: &hdrRemoval,
+				FARID:           &farid,
+				QERID:           []uint32{1, 2},
+				URRID:           []uint32{3},
+				SEID:            &seid,
+				PDNType:         &pdnType,
+				PDI: &PDI{
+					SrcIntf: &srcIntf,
+					UEAddr:  net.IP{60, 60, 0, 1},
+					FTEID: &FTEID{
+						TEID:     5,
+						GTPuAddr: net.IP{40, 40, 40, 2},
+					},
+					SDF: &SDFFilter{
+						FD: &FlowDesc{
+							Action: SDF_FILTER_PERMIT,
+							Dir:    SDF_FILTER_OUT,
+							Proto:  17,
+							Src: net.IPNet{
+								IP:   net.IP{10, 0, 0, 0},
+								Mask: net.CIDRMask(8, 32),
+							},
+							Dst: net.IPNet{
+								IP:   net.IP{60, 60, 0, 1},
+								Mask: net.CIDRMask(32, 32),
+							},
+							SrcPorts: [][]uint16{{53}, {1000, 2000}},
+							DstPorts: [][]uint16{{8080}},
+						},
+						TTC: &ttc,
+						SPI: &spi,
+						FL:  &fl,
+						BID: &bid,
+					},
+				},
+			},
+		},
+		{
+			name: "buffering",
+			pdr: PDR{
+				ID:             4,
+				Precedence:     &precedence,
+				FARID:          &farid,
+				RoleAddr:       net.IP{10, 200, 200, 1},
+				UnixSocketPath: &usock,
+				PDI: &PDI{
+					SrcIntf: &srcIntf,
+					UEAddr:  net.IP{60, 60, 0, 3},
+				},
+			},
+		},
+		{
+			name: "ethernet",
+			pdr: PDR{
+				ID:    2,
+				FARID: &farid,
+				PDI: &PDI{
+					SrcIntf: &srcIntf,
+					EPFs: []EthPktFilter{
+						{
+							EthFilterID: &epfid,
+							MACAddrs: []MACAddrFields{
+								{
+									SourceMACAddress:      "00:11:22:33:44:55",
+									DestinationMACAddress: "66:77:88:99:aa:bb",
+								},
+							},
+							Ethertype:  &ethertype,
+							Properties: &epfProps,
+							CTAG: &VLANTag{
+								Flags: VLAN_TAG_PCP | VLAN_TAG_VID,
+								PCP:   5,
+								VID:   0x123,
+							},
+							STAG: &VLANTag{
+								Flags: VLAN_TAG_DEI | VLAN_TAG_VID,
+								DEI:   1,
+								VID:   0xfff,
+							},
+							SDFs: []SDFFilter{
+								{
+									FD: &FlowDesc{
+										Action: SDF_FILTER_PERMIT,
+										Dir:    SDF_FILTER_OUT,
+										Proto:  6,
+										Src: net.IPNet{
+											IP:   net.IP{10, 0, 0, 0},
+											Mask: net.CIDRMask(8, 32),
+										},
+										Dst: net.IPNet{
+											IP:   net.IP{10, 0, 0, 1},
+											Mask: net.CIDRMask(32, 32),
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "framed routes",
+			pdr: PDR{
+				ID:    3,
+				FARID: &farid,
+				PDI: &PDI{
+					SrcIntf: &srcIntf,
+					UEAddr:  net.IP{60, 60, 0, 2},
+					FramedRoutes: []net.IPNet{
+						{
+							IP:   net.IP{192, 168, 10, 0},
+							Mask: net.CIDRMask(24, 32),
+						},
+						{
+							IP:   net.ParseIP("2001:db8:100::"),
+							Mask: net.CIDRMask(56, 128),
+						},
+					},
+					FTEID: &FTEID{
+						TEID:     6,
+						GTPuAddr: net.IP{40, 40, 40, 2},
+					},
+				},
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			attrs := []nl.Attr{
+				{
+					Type:  PDR_ID,
+					Value: nl.AttrU16(tc.pdr.ID),
+				},
+			}
+			if tc.pdr.SEID != nil {
+				attrs = append(attrs, nl.Attr{
+					Type:  PDR_SEID,
+					Value: nl.AttrU64(*tc.pdr.SEID),
+				})
+			}
+			v, err := EncodePDR(&tc.pdr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			attrs = append(attrs, v...)
+			pdr, err := DecodePDR(encodeAttrs(t, attrs))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(pdr, &tc.pdr) {
+				t.Errorf("want %+v; but got %+v\n", tc.pdr, *pdr)
+			}
+		})
+	}
+}
+
+func TestVLANTag(t *testing.T) {
+	tag := VLANTag{
+		Flags: VLAN_TAG_PCP | VLAN_TAG_DEI | VLAN_TAG_VID,
+		PCP:   7,
+		DEI:   1,
+		VID:   0xabc,
+	}
+	b := EncodeVLANTag(tag)
+	want := []byte{0x07, 0xaf, 0xbc}
+	if !reflect.DeepEqual(b, want) {
+		t.Errorf("want % x; but got % x", want, b)
+	}
+	if got := DecodeVLANTag(b); got != tag {
+		t.Errorf("want %+v; but got %+v", tag, got)
+	}
+}
+
+func TestEncodeMACAddrFields(t *testing.T) {
+	for _, s := range []string{"bogus", "00:11:22:33:44:55:66:77"} {
+		_, err := ParseMACAddr(s)
+		if err == nil {
+			t.Errorf("ParseMACAddr(%q): want error", s)
+		}
+		pdr := PDR{
+			PDI: &PDI{
+				EPFs: []EthPktFilter{
+					{MACAddrs: []MACAddrFields{{DestinationMACAddress: s}}},
+				},
+			},
+		}
+		_, err = EncodePDR(&pdr)
+		if err == nil {
+			t.Errorf("EncodePDR with MAC address %q: want error", s)
+		}
+	}
+
+	f := MACAddrFields{SourceMACAddress: "00-11-22-33-44-55"}
+	attrs, err := EncodeMACAddrFields(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := DecodeMACAddrFields(encodeAttrs(t, attrs))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "00:11:22:33:44:55"; got.SourceMACAddress != want {
+		t.Errorf("want %v; but got %v", want, got.SourceMACAddress)
+	}
+}
@@ -19,22 +19,39 @@ const (
 )
 
 type QER struct {
-	ID     uint32
-	Gate   uint8
-	MBR    MBR
-	GBR    GBR
-	CorrID uint32
-	RQI    uint8
-	QFI    uint8
-	PPI    uint8
-	PDRIDs []uint16
-	SEID   *uint64
+	ID      uint32
+	Gate    uint8
+	MBR     MBR
+	GBR     GBR
+	CorrID  uint32
+	RQI     uint8
+	QFI     uint8
+	PPI     uint8
+	PDRIDs  []uint16
+	SEID    *uint64
+	Unknown []RawAttr `json:",omitempty"`
+}
+
+var qerSpec = attrSpec{
+	name: "QER",
+	attrs: map[int]attrSize{
+		QER_ID:             {name: "ID", size: 4},
+		QER_GATE:           {name: "GATE", size: 1},
+		QER_MBR:            {name: "MBR"},
+		QER_GBR:            {name: "GBR"},
+		QER_CORR_ID:        {name: "CORR_ID", size: 4},
+		QER_RQI:            {name: "RQI", size: 1},
+		QER_QFI:            {name: "QFI", size: 1},
+		QER_PPI:            {name: "PPI", size: 1},
+		QER_RELATED_TO_PDR: {name: "RELATED_TO_PDR", size: 2, list: true},
+		QER_SEID:           {name: "SEID", size: 8},
+	},
 }
 
 func DecodeQER(b []byte) (*QER, error) {
 	qer := new(QER)
 	for len(b) > 0 {
-		hdr, n, err := nl.DecodeAttrHdr(b)
+		hdr, n, err := qerSpec.decodeAttrHdr(b)
 		if err != nil {
 			return nil, err
 		}
@@ -47,13 +64,13 @@ func DecodeQER(b []byte) (*QER, error) {
 		case QER_MBR:
 			mbr, err := DecodeMBR(b[n:attrLen])
 			if err != nil {
-				return nil, err
+				return nil, qerSpec.wrap(err)
 			}
 			qer.MBR = mbr
 		case QER_GBR:
 			gbr, err := DecodeGBR(b[n:attrLen])
 			if err != nil {
-				return nil, err
+				return nil, qerSpec.wrap(err)
 			}
 			qer.GBR = gbr
 		case QER_CORR_ID:
@@ -74,12 +91,58 @@ func DecodeQER(b []byte) (*QER, error) {
 		case QER_SEID:
 			v := native.Uint64(b[n:attrLen])
 			qer.SEID = &v
+		default:
+			qer.Unknown = append(qer.Unknown, rawAttr(hdr, b[n:attrLen]))
 		}
-		b = b[hdr.Len.Align():]
+		b = nextAttr(b, hdr)
 	}
 	return qer, nil
 }
 
+// EncodeQER returns the attributes of qer for CreateQEROID and UpdateQEROID.
+// QER_ID and QER_SEID are not included; they are carried by the OID.
+// PDRIDs is reported by the kernel and is not encoded.
+// A zero MBR or GBR is omitted.
+func EncodeQER(qer *QER) []nl.Attr {
+	attrs := []nl.Attr{
+		{
+			Type:  QER_GATE,
+			Value: nl.AttrU8(qer.Gate),
+		},
+	}
+	if !qer.MBR.isZero() {
+		attrs = append(attrs, nl.Attr{
+			Type:  QER_MBR,
+			Value: nl.AttrList(EncodeMBR(qer.MBR)),
+		})
+	}
+	if !qer.GBR.isZero() {
+		attrs = append(attrs, nl.Attr{
+			Type:  QER_GBR,
+			Value: nl.AttrList(EncodeGBR(qer.GBR)),
+		})
+	}
+	attrs = append(attrs, nl.AttrList{
+		{
+			Type:  QER_CORR_ID,
+			Value: nl.AttrU32(qer.CorrID),
+		},
+		{
+			Type:  QER_RQI,
+			Value: nl.AttrU8(qer.RQI),
+		},
+		{
+			Type:  QER_QFI,
+			Value: nl.AttrU8(qer.QFI),
+		},
+		{
+			Type:  QER_PPI,
+			Value: nl.AttrU8(qer.PPI),
+		},
+	}...)
+	return attrs
+}
+
 const (
 	QER_MBR_UL_HIGH32 = iota + 1
 	QER_MBR_UL_LOW8
@@ -90,16 +153,27 @@ const (
 type MBR struct {
 	ULHigh  uint32
 	ULLow   uint8
-	UL_Kbps uint64 // for viewer-friendly
+	UL_Kbps BitRate // for viewer-friendly
 	DLHigh  uint32
 	DLLow   uint8
-	DL_Kbps uint64 // for viewer-friendly
+	DL_Kbps BitRate   // for viewer-friendly
+	Unknown []RawAttr `json:",omitempty"`
+}
+
+var mbrSpec = attrSpec{
+	name: "MBR",
+	attrs: map[int]attrSize{
+		QER_MBR_UL_HIGH32: {name: "UL_HIGH32", size: 4},
+		QER_MBR_UL_LOW8:   {name: "UL_LOW8", size: 1},
+		QER_MBR_DL_HIGH32: {name: "DL_HIGH32", size: 4},
+		QER_MBR_DL_LOW8:   {name: "DL_LOW8", size: 1},
+	},
 }
 
 func DecodeMBR(b []byte) (MBR, error) {
 	var mbr MBR
 	for len(b) > 0 {
-		hdr, n, err := nl.DecodeAttrHdr(b)
+		hdr, n, err := mbrSpec.decodeAttrHdr(b)
 		if err != nil {
 			return mbr, err
 		}
@@ -113,16 +187,55 @@ func DecodeMBR(b []byte) (MBR, error) {
 			mbr.DLHigh = native.Uint32(b[n:attrLen])
 		case QER_MBR_DL_LOW8:
 			mbr.DLLow = b[n]
+		default:
+			mbr.Unknown = append(mbr.Unknown, rawAttr(hdr, b[n:attrLen]))
 		}
-		b = b[hdr.Len.Align():]
+		b = nextAttr(b, hdr)
 	}
 
-	mbr.UL_Kbps = uint64(mbr.ULHigh)<<8 + uint64(mbr.ULLow)
-	mbr.DL_Kbps = uint64(mbr.DLHigh)<<8 + uint64(mbr.DLLow)
+	mbr.UL_Kbps = BitRateOf(mbr.ULHigh, mbr.ULLow)
+	mbr.DL_Kbps = BitRateOf(mbr.DLHigh, mbr.DLLow)
 
 	return mbr, nil
 }
 
+// NewMBR returns the MBR of the uplink and downlink rates ul and dl.
+func NewMBR(ul, dl BitRate) MBR {
+	var mbr MBR
+	mbr.ULHigh, mbr.ULLow = ul.Split()
+	mbr.DLHigh, mbr.DLLow = dl.Split()
+	mbr.UL_Kbps = ul
+	mbr.DL_Kbps = dl
+	return mbr
+}
+
+func (mbr MBR) isZero() bool {
+	return mbr.ULHigh == 0 && mbr.ULLow == 0 && mbr.DLHigh == 0 && mbr.DLLow == 0
+}
+
+// EncodeMBR encodes the HIGH32/LOW8 pairs of mbr; UL_Kbps and DL_Kbps are
+// ignored.
+func EncodeMBR(mbr MBR) []nl.Attr {
+	return []nl.Attr{
+		{
+			Type:  QER_MBR_UL_HIGH32,
+			Value: nl.AttrU32(mbr.ULHigh),
+		},
+		{
+			Type:  QER_MBR_UL_LOW8,
+			Value: nl.AttrU8(mbr.ULLow),
+		},
+		{
+			Type:  QER_MBR_DL_HIGH32,
+			Value: nl.AttrU32(mbr.DLHigh),
+		},
+		{
+			Type:  QER_MBR_DL_LOW8,
+			Value: nl.AttrU8(mbr.DLLow),
+		},
+	}
+}
+
 const (
 	QER_GBR_UL_HIGH32 = iota + 1
 	QER_GBR_UL_LOW8
@@ -133,16 +246,27 @@ const (
 type GBR struct {
 	ULHigh  uint32
 	ULLow   uint8
-	UL_Kbps uint64 // for viewer-friendly
+	UL_Kbps BitRate // for viewer-friendly
 	DLHigh  uint32
 	DLLow   uint8
-	DL_Kbps uint64 // for viewer-friendly
+	DL_Kbps BitRate   // for viewer-friendly
+	Unknown []RawAttr `json:",omitempty"`
+}
+
+var gbrSpec = attrSpec{
+	name: "GBR",
+	attrs: map[int]attrSize{
+		QER_GBR_UL_HIGH32: {name: "UL_HIGH32", size: 4},
+		QER_GBR_UL_LOW8:   {name: "UL_LOW8", size: 1},
+		QER_GBR_DL_HIGH32: {name: "DL_HIGH32", size: 4},
+		QER_GBR_DL_LOW8:   {name: "DL_LOW8", size: 1},
+	},
 }
 
 func DecodeGBR(b []byte) (GBR, error) {
 	var gbr GBR
 	for len(b) > 0 {
-		hdr, n, err := nl.DecodeAttrHdr(b)
+		hdr, n, err := gbrSpec.decodeAttrHdr(b)
 		if err != nil {
 			return gbr, err
 		}
@@ -156,12 +280,51 @@ func DecodeGBR(b []byte) (GBR, error) {
 			gbr.DLHigh = native.Uint32(b[n:attrLen])
 		case QER_GBR_DL_LOW8:
 			gbr.DLLow = b[n]
+		default:
+			gbr.Unknown = append(gbr.Unknown, rawAttr(hdr, b[n:attrLen]))
 		}
-		b = b[hdr.Len.Align():]
+		b = nextAttr(b, hdr)
 	}
 
-	gbr.UL_Kbps = uint64(gbr.ULHigh)<<8 + uint64(gbr.ULLow)
-	gbr.DL_Kbps = uint64(gbr.DLHigh)<<8 + uint64(gbr.DLLow)
+	gbr.UL_Kbps = BitRateOf(gbr.ULHigh, gbr.ULLow)
+	gbr.DL_Kbps = BitRateOf(gbr.DLHigh, gbr.DLLow)
 
 	return gbr, nil
 }
+
+// NewGBR returns the GBR of the uplink and downlink rates ul and dl.
+func NewGBR(ul, dl BitRate) GBR {
+	var gbr GBR
+	gbr.ULHigh, gbr.ULLow = ul.Split()
+	gbr.DLHigh, gbr.DLLow = dl.Split()
+	gbr.UL_Kbps = ul
+	gbr.DL_Kbps = dl
+	return gbr
+}
+
+func (gbr GBR) isZero() bool {
+	return gbr.ULHigh == 0 && gbr.ULLow == 0 && gbr.DLHigh == 0 && gbr.DLLow == 0
+}
+
+// EncodeGBR encodes the HIGH32/LOW8 pairs of gbr; UL_Kbps and DL_Kbps are
+// ignored.
+func EncodeGBR(gbr GBR) []nl.Attr {
+	return []nl.Attr{
+		{
+			Type:  QER_GBR_UL_HIGH32,
+			Value: nl.AttrU32(gbr.ULHigh),
+		},
+		{
+			Type:  QER_GBR_UL_LOW8,
+			Value: nl.AttrU8(gbr.ULLow),
+		},
+		{
+			Type:  QER_GBR_DL_HIGH32,
+			Value: nl.AttrU32(gbr.DLHigh),
+		},
+		{
+			Type:  QER_GBR_DL_LOW8,
+			Value: nl.AttrU8(gbr.DLLow),
+		},
+	}
+}
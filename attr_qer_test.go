@@ -0,0 +1,77 @@
+package gtp5gnl
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/khirono/go-nl"
+)
+
+func TestEncodeQER(t *testing.T) {
+	seid := uint64(0x1234)
+
+	cases := []struct {
+		name string
+		qer  QER
+	}{
+		{
+			name: "gate",
+			qer: QER{
+				ID:   1,
+				Gate: 1,
+			},
+		},
+		{
+			name: "bitrate",
+			qer: QER{
+				ID:   2,
+				Gate: 0,
+				MBR: MBR{
+					ULHigh:  0x1,
+					ULLow:   0x2,
+					UL_Kbps: 0x102,
+					DLHigh:  0x3,
+					DLLow:   0x4,
+					DL_Kbps: 0x304,
+				},
+				GBR: GBR{
+					ULHigh:  0x5,
+					ULLow:   0x6,
+					UL_Kbps: 0x506,
+					DLHigh:  0x7,
+					DLLow:   0x8,
+					DL_Kbps: 0x708,
+				},
+				CorrID: 10,
+				RQI:    1,
+				QFI:    9,
+				PPI:    3,
+				SEID:   &seid,
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			attrs := []nl.Attr{
+				{
+					Type:  QER_ID,
+					Value: nl.AttrU32(tc.qer.ID),
+				},
+			}
+			if tc.qer.SEID != nil {
+				attrs = append(attrs, nl.Attr{
+					Type:  QER_SEID,
+					Value: nl.AttrU64(*tc.qer.SEID),
+				})
+			}
+			attrs = append(attrs, EncodeQER(&tc.qer)...)
+			qer, err := DecodeQER(encodeAttrs(t, attrs))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(qer, &tc.qer) {
+				t.Errorf("want %+v; but got %+v\n", tc.qer, *qer)
+			}
+		})
+	}
+}
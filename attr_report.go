@@ -3,8 +3,6 @@ package gtp5gnl
 import (
 	"time"
 	"unsafe"
-
-	"github.com/khirono/go-nl"
 )
 
 // for UPF Usage Statistic
@@ -33,6 +31,7 @@ type UsageStatistic struct {
 	UlPktTx    uint64
 	DlPktRx    uint64
 	DlPktTx    uint64
+	Unknown    []RawAttr `json:",omitempty"`
 }
 
 const (
@@ -74,12 +73,96 @@ const (
 type USAReport struct {
 	URRID          uint32
 	URSEQN         uint32
-	USARTrigger    uint32
+	USARTrigger    UsageReportTrigger
 	VolMeasurement VolumeMeasurement
 	QueryUrrRef    uint32
 	StartTime      time.Time
 	EndTime        time.Time
 	SEID           uint64
+	Unknown        []RawAttr `json:",omitempty"`
+}
+
+// UsageReportTrigger is the Usage Report Trigger IE (TS 29.244 8.2.41).
+type UsageReportTrigger uint32
+
+const (
+	USAGE_REPORT_TRIGGER_PERIO UsageReportTrigger = 1 << iota // Periodic Reporting
+	USAGE_REPORT_TRIGGER_VOLTH                                // Volume Threshold
+	USAGE_REPORT_TRIGGER_TIMTH                                // Time Threshold
+	USAGE_REPORT_TRIGGER_QUHTI                                // Quota Holding Time
+	USAGE_REPORT_TRIGGER_START                                // Start of Traffic
+	USAGE_REPORT_TRIGGER_STOPT                                // Stop of Traffic
+	USAGE_REPORT_TRIGGER_DROTH                                // Dropped DL Traffic Threshold
+	USAGE_REPORT_TRIGGER_IMMER                                // Immediate Report
+	USAGE_REPORT_TRIGGER_VOLQU                                // Volume Quota
+	USAGE_REPORT_TRIGGER_TIMQU                                // Time Quota
+	USAGE_REPORT_TRIGGER_LIUSA                                // Linked Usage Reporting
+	USAGE_REPORT_TRIGGER_TERMR                                // Termination Report
+	USAGE_REPORT_TRIGGER_MONIT                                // Monitoring Time
+	USAGE_REPORT_TRIGGER_ENVCL                                // Envelope Closure
+	USAGE_REPORT_TRIGGER_MACAR                                // MAC Addresses Reporting
+	USAGE_REPORT_TRIGGER_EVETH                                // Event Threshold
+	USAGE_REPORT_TRIGGER_EVEQU                                // Event Quota
+	USAGE_REPORT_TRIGGER_TEBUR                                // Termination By UP function Report
+	USAGE_REPORT_TRIGGER_IPMJL                                // IP Multicast Join/Leave
+	USAGE_REPORT_TRIGGER_QUVTI                                // Quota Validity Time
+	USAGE_REPORT_TRIGGER_EMRRE                                // End Marker Reception REport
+	USAGE_REPORT_TRIGGER_UPINT                                // User Plane Inactivity Timer
+)
+
+var usageReportTriggerNames = []flagName[UsageReportTrigger]{
+	{USAGE_REPORT_TRIGGER_PERIO, "PERIO"},
+	{USAGE_REPORT_TRIGGER_VOLTH, "VOLTH"},
+	{USAGE_REPORT_TRIGGER_TIMTH, "TIMTH"},
+	{USAGE_REPORT_TRIGGER_QUHTI, "QUHTI"},
+	{USAGE_REPORT_TRIGGER_START, "START"},
+	{USAGE_REPORT_TRIGGER_STOPT, "STOPT"},
+	{USAGE_REPORT_TRIGGER_DROTH, "DROTH"},
+	{USAGE_REPORT_TRIGGER_IMMER, "IMMER"},
+	{USAGE_REPORT_TRIGGER_VOLQU, "VOLQU"},
+	{USAGE_REPORT_TRIGGER_TIMQU, "TIMQU"},
+	{USAGE_REPORT_TRIGGER_LIUSA, "LIUSA"},
+	{USAGE_REPORT_TRIGGER_TERMR, "TERMR"},
+	{USAGE_REPORT_TRIGGER_MONIT, "MONIT"},
+	{USAGE_REPORT_TRIGGER_ENVCL, "ENVCL"},
+	{USAGE_REPORT_TRIGGER_MACAR, "MACAR"},
+	{USAGE_REPORT_TRIGGER_EVETH, "EVETH"},
+	{USAGE_REPORT_TRIGGER_EVEQU, "EVEQU"},
+	{USAGE_REPORT_TRIGGER_TEBUR, "TEBUR"},
+	{USAGE_REPORT_TRIGGER_IPMJL, "IPMJL"},
+	{USAGE_REPORT_TRIGGER_QUVTI, "QUVTI"},
+	{USAGE_REPORT_TRIGGER_EMRRE, "EMRRE"},
+	{USAGE_REPORT_TRIGGER_UPINT, "UPINT"},
+}
+
+func (u UsageReportTrigger) Has(flag UsageReportTrigger) bool {
+	return u&flag == flag
+}
+
+// String returns the set flags joined by "|", e.g. "VOLTH|TERMR".
+func (u UsageReportTrigger) String() string {
+	return formatFlags(u, usageReportTriggerNames)
+}
+
+// ParseUsageReportTrigger parses a number or flag names separated by "|" or ",",
+// e.g. "TERMR" or "perio,volth".
+func ParseUsageReportTrigger(s string) (UsageReportTrigger, error) {
+	return parseFlags(s, usageReportTriggerNames, "usage report trigger")
+}
+
+// MarshalJSON encodes u as a list of flag names.
+func (u UsageReportTrigger) MarshalJSON() ([]byte, error) {
+	return marshalFlags(u, usageReportTriggerNames)
+}
+
+// UnmarshalJSON accepts a list of flag names or a number.
+func (u *UsageReportTrigger) UnmarshalJSON(b []byte) error {
+	v, err := unmarshalFlags(b, usageReportTriggerNames, "usage report trigger")
+	if err != nil {
+		return err
+	}
+	*u = v
+	return nil
 }
 
 type VolumeMeasurement struct {
@@ -90,6 +173,7 @@ type VolumeMeasurement struct {
 	TotalPktNum    uint64
 	UplinkPktNum   uint64
 	DownlinkPktNum uint64
+	Unknown        []RawAttr `json:",omitempty"`
 }
 
 // The maximun netlink message size is 16K, and the body for the attibutes are 7856 Bytes
@@ -137,15 +221,30 @@ func MaxNetlinkUsageReportNum() int {
 	return MAX_NETLINK_MSG_BODY_SIZE / size
 }
 
+var volumeMeasurementSpec = attrSpec{
+	name: "VOLUME_MEASUREMENT",
+	attrs: map[int]attrSize{
+		UR_VOLUME_MEASUREMENT_FLAGS:    {name: "FLAGS", size: 1},
+		UR_VOLUME_MEASUREMENT_TOVOL:    {name: "TOVOL", size: 8},
+		UR_VOLUME_MEASUREMENT_UVOL:     {name: "UVOL", size: 8},
+		UR_VOLUME_MEASUREMENT_DVOL:     {name: "DVOL", size: 8},
+		UR_VOLUME_MEASUREMENT_TOPACKET: {name: "TOPACKET", size: 8},
+		UR_VOLUME_MEASUREMENT_UPACKET:  {name: "UPACKET", size: 8},
+		UR_VOLUME_MEASUREMENT_DPACKET:  {name: "DPACKET", size: 8},
+	},
+}
+
 func decodeVolumeMeasurement(b []byte) (VolumeMeasurement, error) {
 	var VolMeasurement VolumeMeasurement
 	for len(b) > 0 {
-		hdr, n, err := nl.DecodeAttrHdr(b)
+		hdr, n, err := volumeMeasurementSpec.decodeAttrHdr(b)
 		if err != nil {
 			return VolMeasurement, err
 		}
 		attrLen := int(hdr.Len)
 		switch hdr.MaskedType() {
+		case UR_VOLUME_MEASUREMENT_FLAGS:
+			VolMeasurement.Flag |= b[n]
 		case UR_VOLUME_MEASUREMENT_TOVOL:
 			v := native.Uint64(b[n:attrLen])
 			VolMeasurement.TotalVolume = v
@@ -171,19 +270,33 @@ func decodeVolumeMeasurement(b []byte) (VolumeMeasurement, error) {
 			VolMeasurement.DownlinkPktNum = v
 			VolMeasurement.Flag |= DLNOP
 		default:
-			return VolMeasurement, nil
+			VolMeasurement.Unknown = append(VolMeasurement.Unknown, rawAttr(hdr, b[n:attrLen]))
 		}
 
-		b = b[hdr.Len.Align():]
+		b = nextAttr(b, hdr)
 	}
 	return VolMeasurement, nil
 }
 
+var usageStatisticSpec = attrSpec{
+	name: "USTAT",
+	attrs: map[int]attrSize{
+		USTAT_UL_VOL_RX: {name: "UL_VOL_RX", size: 8},
+		USTAT_UL_VOL_TX: {name: "UL_VOL_TX", size: 8},
+		USTAT_DL_VOL_RX: {name: "DL_VOL_RX", size: 8},
+		USTAT_DL_VOL_TX: {name: "DL_VOL_TX", size: 8},
+		USTAT_UL_PKT_RX: {name: "UL_PKT_RX", size: 8},
+		USTAT_UL_PKT_TX: {name: "UL_PKT_TX", size: 8},
+		USTAT_DL_PKT_RX: {name: "DL_PKT_RX", size: 8},
+		USTAT_DL_PKT_TX: {name: "DL_PKT_TX", size: 8},
+	},
+}
+
 func DecodeUsageStatistic(b []byte) (*UsageStatistic, error) {
 	ustat := new(UsageStatistic)
 
 	for len(b) > 0 {
-		hdr, n, err := nl.DecodeAttrHdr(b)
+		hdr, n, err := usageStatisticSpec.decodeAttrHdr(b)
 		if err != nil {
 			return nil, err
 		}
@@ -205,9 +318,11 @@ func DecodeUsageStatistic(b []byte) (*UsageStatistic, error) {
 			ustat.DlPktRx = native.Uint64(b[n:attrLen])
 		case USTAT_DL_PKT_TX:
 			ustat.DlPktTx = native.Uint64(b[n:attrLen])
+		default:
+			ustat.Unknown = append(ustat.Unknown, rawAttr(hdr, b[n:attrLen]))
 		}
 
-		b = b[hdr.Len.Align():]
+		b = nextAttr(b, hdr)
 	}
 
 	// total volume count
@@ -221,11 +336,18 @@ func DecodeUsageStatistic(b []byte) (*UsageStatistic, error) {
 	return ustat, nil
 }
 
+var usaReportsSpec = attrSpec{
+	name: "",
+	attrs: map[int]attrSize{
+		UR: {name: "UR"},
+	},
+}
+
 func DecodeAllUSAReports(b []byte) ([]USAReport, error) {
 	var usars []USAReport
 
 	for len(b) > 0 {
-		hdr, n, err := nl.DecodeAttrHdr(b)
+		hdr, n, err := usaReportsSpec.decodeAttrHdr(b)
 		if err != nil {
 			return nil, err
 		}
@@ -234,21 +356,35 @@ func DecodeAllUSAReports(b []byte) ([]USAReport, error) {
 		case UR:
 			r, err := decodeUSAReport(b[n:attrLen])
 			if err != nil {
-				return nil, err
+				return nil, usaReportsSpec.wrap(err)
 			}
 			usars = append(usars, *r)
 		}
 
-		b = b[hdr.Len.Align():]
+		b = nextAttr(b, hdr)
 	}
 	return usars, nil
 }
 
+var usaReportSpec = attrSpec{
+	name: "UR",
+	attrs: map[int]attrSize{
+		UR_URRID:                {name: "URRID", size: 4},
+		UR_USAGE_REPORT_TRIGGER: {name: "USAGE_REPORT_TRIGGER", size: 4},
+		UR_URSEQN:               {name: "URSEQN", size: 4},
+		UR_VOLUME_MEASUREMENT:   {name: "VOLUME_MEASUREMENT"},
+		UR_QUERY_URR_REFERENCE:  {name: "QUERY_URR_REFERENCE", size: 4},
+		UR_START_TIME:           {name: "START_TIME", size: 8},
+		UR_END_TIME:             {name: "END_TIME", size: 8},
+		UR_SEID:                 {name: "SEID", size: 8},
+	},
+}
+
 func decodeUSAReport(b []byte) (*USAReport, error) {
 	report := new(USAReport)
 
 	for len(b) > 0 {
-		hdr, n, err := nl.DecodeAttrHdr(b)
+		hdr, n, err := usaReportSpec.decodeAttrHdr(b)
 		if err != nil {
 			return nil, err
 		}
@@ -257,15 +393,17 @@ func decodeUSAReport(b []byte) (*USAReport, error) {
 		case UR_URRID:
 			report.URRID = native.Uint32(b[n:attrLen])
 		case UR_USAGE_REPORT_TRIGGER:
-			report.USARTrigger = native.Uint32(b[n:attrLen])
+			report.USARTrigger = UsageReportTrigger(native.Uint32(b[n:attrLen]))
 		case UR_URSEQN:
 			report.URSEQN = native.Uint32(b[n:attrLen])
 		case UR_VOLUME_MEASUREMENT:
 			volMeasurement, err := decodeVolumeMeasurement(b[n:attrLen])
 			if err != nil {
-				return nil, err
+				return nil, usaReportSpec.wrap(err)
 			}
 			report.VolMeasurement = volMeasurement
+		case UR_QUERY_URR_REFERENCE:
+			report.QueryUrrRef = native.Uint32(b[n:attrLen])
 		case UR_START_TIME:
 			v := native.Uint64(b[n:attrLen])
 			report.StartTime = time.Unix(0, int64(v))
@@ -274,9 +412,11 @@ func decodeUSAReport(b []byte) (*USAReport, error) {
 			report.EndTime = time.Unix(0, int64(v))
 		case UR_SEID:
 			report.SEID = native.Uint64(b[n:attrLen])
+		default:
+			report.Unknown = append(report.Unknown, rawAttr(hdr, b[n:attrLen]))
 		}
 
-		b = b[hdr.Len.Align():]
+		b = nextAttr(b, hdr)
 	}
 	return report, nil
 }
@@ -0,0 +1,92 @@
+package gtp5gnl
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/khirono/go-nl"
+)
+
+func TestDecodeUSAReport(t *testing.T) {
+	attrs := []nl.Attr{
+		{
+			Type: UR,
+			Value: nl.AttrList{
+				{
+					Type:  UR_URRID,
+					Value: nl.AttrU32(1),
+				},
+				{
+					Type:  UR_USAGE_REPORT_TRIGGER,
+					Value: nl.AttrU32(2),
+				},
+				{
+					Type:  UR_URSEQN,
+					Value: nl.AttrU32(3),
+				},
+				{
+					Type: UR_VOLUME_MEASUREMENT,
+					Value: nl.AttrList{
+						{
+							Type:  UR_VOLUME_MEASUREMENT_FLAGS,
+							Value: nl.AttrU8(TOVOL | ULVOL | DLVOL),
+						},
+						{
+							Type:  UR_VOLUME_MEASUREMENT_TOVOL,
+							Value: nl.AttrU64(300),
+						},
+						{
+							Type:  UR_VOLUME_MEASUREMENT_UVOL,
+							Value: nl.AttrU64(100),
+						},
+						{
+							Type:  UR_VOLUME_MEASUREMENT_DVOL,
+							Value: nl.AttrU64(200),
+						},
+					},
+				},
+				{
+					Type:  UR_QUERY_URR_REFERENCE,
+					Value: nl.AttrU32(4),
+				},
+				{
+					Type:  UR_START_TIME,
+					Value: nl.AttrU64(1700000000000000000),
+				},
+				{
+					Type:  UR_END_TIME,
+					Value: nl.AttrU64(1700000060000000000),
+				},
+				{
+					Type:  UR_SEID,
+					Value: nl.AttrU64(0x1234),
+				},
+			},
+		},
+	}
+	want := []USAReport{
+		{
+			URRID:       1,
+			USARTrigger: 2,
+			URSEQN:      3,
+			VolMeasurement: VolumeMeasurement{
+				Flag:           TOVOL | ULVOL | DLVOL,
+				TotalVolume:    300,
+				UplinkVolume:   100,
+				DownlinkVolume: 200,
+			},
+			QueryUrrRef: 4,
+			StartTime:   time.Unix(0, 1700000000000000000),
+			EndTime:     time.Unix(0, 1700000060000000000),
+			SEID:        0x1234,
+		},
+	}
+	reports, err := DecodeAllUSAReports(encodeAttrs(t, attrs))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(reports, want) {
+		t.Errorf("want %+v; but got %+v", want, reports)
+	}
+}
@@ -0,0 +1,214 @@
+package gtp5gnl
+
+import (
+	"errors"
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/khirono/go-nl"
+)
+
+func encodeAttrs(t testing.TB, attrs []nl.Attr) []byte {
+	t.Helper()
+	al := nl.AttrList(attrs)
+	b := make([]byte, al.Len())
+	_, err := al.Encode(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func encodePDR(t testing.TB, pdr *PDR) []nl.Attr {
+	t.Helper()
+	attrs, err := EncodePDR(pdr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return attrs
+}
+
+func TestDecodeUnknownAttr(t *testing.T) {
+	attrs := []nl.Attr{
+		{
+			Type:  PDR_ID,
+			Value: nl.AttrU16(1),
+		},
+		{
+			Type:  0x7f,
+			Value: nl.AttrU32(0xdeadbeef),
+		},
+		{
+			Type: PDR_PDI,
+			Value: nl.AttrList{
+				{
+					Type:  0x7e,
+					Value: nl.AttrList{{Type: 1, Value: nl.AttrU8(5)}},
+				},
+				{
+					Type:  PDI_UE_ADDR_IPV4,
+					Value: nl.AttrBytes(net.IPv4(60, 60, 0, 1).To4()),
+				},
+			},
+		},
+	}
+	pdr, err := DecodePDR(encodeAttrs(t, attrs))
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := make([]byte, 4)
+	native.PutUint32(v, 0xdeadbeef)
+	want := []RawAttr{{Type: 0x7f, Value: v}}
+	if !reflect.DeepEqual(pdr.Unknown, want) {
+		t.Errorf("want %v; but got %v", want, pdr.Unknown)
+	}
+	if pdr.PDI == nil || !pdr.PDI.UEAddr.Equal(net.IPv4(60, 60, 0, 1)) {
+		t.Fatalf("PDI not decoded: %+v", pdr.PDI)
+	}
+	nested := encodeAttrs(t, []nl.Attr{{Type: 1, Value: nl.AttrU8(5)}})
+	wantPDI := []RawAttr{{Type: 0x7e, Value: nested}}
+	if !reflect.DeepEqual(pdr.PDI.Unknown, wantPDI) {
+		t.Errorf("want %v; but got %v", wantPDI, pdr.PDI.Unknown)
+	}
+	for _, attr := range encodePDR(t, pdr) {
+		if attr.Type == 0x7f {
+			t.Errorf("unknown attribute encoded")
+		}
+	}
+}
+
+func TestDecodeUnknownVolumeMeasurement(t *testing.T) {
+	attrs := []nl.Attr{
+		{
+			Type: UR,
+			Value: nl.AttrList{
+				{
+					Type: UR_VOLUME_MEASUREMENT,
+					Value: nl.AttrList{
+						{
+							Type:  0x7f,
+							Value: nl.AttrU8(1),
+						},
+						{
+							Type:  UR_VOLUME_MEASUREMENT_TOVOL,
+							Value: nl.AttrU64(100),
+						},
+					},
+				},
+				{
+					Type:  UR_URRID,
+					Value: nl.AttrU32(3),
+				},
+			},
+		},
+	}
+	reports, err := DecodeAllUSAReports(encodeAttrs(t, attrs))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reports) != 1 {
+		t.Fatalf("want 1 report; but got %v", len(reports))
+	}
+	r := reports[0]
+	if r.URRID != 3 || r.VolMeasurement.TotalVolume != 100 {
+		t.Errorf("attributes after the unknown one were dropped: %+v", r)
+	}
+	if len(r.VolMeasurement.Unknown) != 1 || r.VolMeasurement.Unknown[0].Type != 0x7f {
+		t.Errorf("want unknown attribute 0x7f; but got %v", r.VolMeasurement.Unknown)
+	}
+}
+
+func TestDecodeError(t *testing.T) {
+	fteid := encodeAttrs(t, []nl.Attr{
+		{
+			Type: PDR_PDI,
+			Value: nl.AttrList{
+				{
+					Type: PDI_F_TEID,
+					Value: nl.AttrList{
+						{
+							Type:  F_TEID_I_TEID,
+							Value: nl.AttrU16(1),
+						},
+					},
+				},
+			},
+		},
+	})
+	removal := encodeAttrs(t, []nl.Attr{{Type: PDR_OUTER_HEADER_REMOVAL}})
+	long := encodeAttrs(t, []nl.Attr{{Type: PDR_PRECEDENCE, Value: nl.AttrU32(1)}})
+	native.PutUint16(long, 12)
+	zero := []byte{0, 0, PDR_ID, 0}
+
+	tests := []struct {
+		name   string
+		decode func() error
+		want   DecodeError
+	}{
+		{
+			name:   "nested",
+			decode: func() error { _, err := DecodePDR(fteid); return err },
+			want:   DecodeError{Path: "PDR.PDI.F_TEID.I_TEID", Len: 2, Want: 4},
+		},
+		{
+			name:   "empty payload",
+			decode: func() error { _, err := DecodePDR(removal); return err },
+			want:   DecodeError{Path: "PDR.OUTER_HEADER_REMOVAL", Len: 0, Want: 1},
+		},
+		{
+			name:   "length beyond buffer",
+			decode: func() error { _, err := DecodePDR(long); return err },
+			want:   DecodeError{Path: "PDR.PRECEDENCE", Len: 8, Want: 12},
+		},
+		{
+			name:   "zero length",
+			decode: func() error { _, err := DecodePDR(zero); return err },
+			want:   DecodeError{Path: "PDR.ID", Len: 0, Want: 4},
+		},
+		{
+			name:   "truncated header",
+			decode: func() error { _, err := DecodeFAR([]byte{8, 0}); return err },
+			want:   DecodeError{Path: "FAR", Len: 2, Want: 4},
+		},
+		{
+			name: "odd list",
+			decode: func() error {
+				_, err := DecodeQER(encodeAttrs(t, []nl.Attr{{Type: QER_RELATED_TO_PDR, Value: nl.AttrBytes{1, 0, 2}}}))
+				return err
+			},
+			want: DecodeError{Path: "QER.RELATED_TO_PDR", Len: 3, Want: 4},
+		},
+		{
+			name: "report",
+			decode: func() error {
+				_, err := DecodeAllUSAReports(encodeAttrs(t, []nl.Attr{{Type: UR, Value: nl.AttrList{{Type: UR_SEID, Value: nl.AttrU32(1)}}}}))
+				return err
+			},
+			want: DecodeError{Path: "UR.SEID", Len: 4, Want: 8},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.decode()
+			var e *DecodeError
+			if !errors.As(err, &e) {
+				t.Fatalf("want *DecodeError; but got %v", err)
+			}
+			if *e != tt.want {
+				t.Errorf("want %+v; but got %+v", tt.want, *e)
+			}
+		})
+	}
+}
+
+func TestDecodeUnpadded(t *testing.T) {
+	b := encodeAttrs(t, []nl.Attr{{Type: BAR_ID, Value: nl.AttrU8(5)}})
+	bar, err := DecodeBAR(b[:5])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bar.ID != 5 {
+		t.Errorf("want ID 5; but got %v", bar.ID)
+	}
+}
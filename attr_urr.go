@@ -31,35 +31,216 @@ const (
 	URR_VOLUME_THRESHOLD_DVOL
 )
 
+// VolumeThreshold is the Volume Threshold IE (TS 29.244 8.2.13), in octets.
+// Flag tells which of the volumes are present: TOVOL, ULVOL and DLVOL.
 type VolumeThreshold struct {
-	flag           uint8
-	totalVolume    uint64
-	uplinkVolume   uint64
-	downlinkVolume uint64
+	Flag           uint8
+	TotalVolume    uint64
+	UplinkVolume   uint64
+	DownlinkVolume uint64
+	Unknown        []RawAttr `json:",omitempty"`
 }
 
+// Has reports whether all the volumes of flag are present.
+func (v VolumeThreshold) Has(flag uint8) bool {
+	return v.Flag&flag == flag
+}
+
+func (v *VolumeThreshold) SetTotalVolume(vol uint64) {
+	v.TotalVolume = vol
+	v.Flag |= TOVOL
+}
+
+func (v *VolumeThreshold) SetUplinkVolume(vol uint64) {
+	v.UplinkVolume = vol
+	v.Flag |= ULVOL
+}
+
+func (v *VolumeThreshold) SetDownlinkVolume(vol uint64) {
+	v.DownlinkVolume = vol
+	v.Flag |= DLVOL
+}
+
+// VolumeQuota is the Volume Quota IE (TS 29.244 8.2.50), in octets.
+// Flag tells which of the volumes are present: TOVOL, ULVOL and DLVOL.
 type VolumeQuota struct {
-	flag           uint8
-	totalVolume    uint64
-	uplinkVolume   uint64
-	downlinkVolume uint64
+	Flag           uint8
+	TotalVolume    uint64
+	UplinkVolume   uint64
+	DownlinkVolume uint64
+	Unknown        []RawAttr `json:",omitempty"`
+}
+
+// Has reports whether all the volumes of flag are present.
+func (v VolumeQuota) Has(flag uint8) bool {
+	return v.Flag&flag == flag
+}
+
+func (v *VolumeQuota) SetTotalVolume(vol uint64) {
+	v.TotalVolume = vol
+	v.Flag |= TOVOL
+}
+
+func (v *VolumeQuota) SetUplinkVolume(vol uint64) {
+	v.UplinkVolume = vol
+	v.Flag |= ULVOL
+}
+
+func (v *VolumeQuota) SetDownlinkVolume(vol uint64) {
+	v.DownlinkVolume = vol
+	v.Flag |= DLVOL
 }
 
 type URR struct {
 	ID           uint32
-	Method       uint8
-	Trigger      uint32
+	Method       MeasurementMethod
+	Trigger      ReportingTrigger
 	Period       *uint32
 	Info         *uint8
 	SEID         *uint64
 	VolThreshold *VolumeThreshold
 	VolQuota     *VolumeQuota
+	Unknown      []RawAttr `json:",omitempty"`
+}
+
+// MeasurementMethod is the Measurement Method IE (TS 29.244 8.2.40).
+type MeasurementMethod uint8
+
+const (
+	MEASUREMENT_METHOD_DURAT MeasurementMethod = 1 << iota // Duration
+	MEASUREMENT_METHOD_VOLUM                               // Volume
+	MEASUREMENT_METHOD_EVENT                               // Event
+)
+
+var measurementMethodNames = []flagName[MeasurementMethod]{
+	{MEASUREMENT_METHOD_DURAT, "DURAT"},
+	{MEASUREMENT_METHOD_VOLUM, "VOLUM"},
+	{MEASUREMENT_METHOD_EVENT, "EVENT"},
+}
+
+func (m MeasurementMethod) Has(flag MeasurementMethod) bool {
+	return m&flag == flag
+}
+
+// String returns the set flags joined by "|", e.g. "DURAT|VOLUM".
+func (m MeasurementMethod) String() string {
+	return formatFlags(m, measurementMethodNames)
+}
+
+// ParseMeasurementMethod parses a number or flag names separated by "|" or ",",
+// e.g. "VOLUM" or "durat,volum".
+func ParseMeasurementMethod(s string) (MeasurementMethod, error) {
+	return parseFlags(s, measurementMethodNames, "measurement method")
+}
+
+// MarshalJSON encodes m as a list of flag names.
+func (m MeasurementMethod) MarshalJSON() ([]byte, error) {
+	return marshalFlags(m, measurementMethodNames)
+}
+
+// UnmarshalJSON accepts a list of flag names or a number.
+func (m *MeasurementMethod) UnmarshalJSON(b []byte) error {
+	v, err := unmarshalFlags(b, measurementMethodNames, "measurement method")
+	if err != nil {
+		return err
+	}
+	*m = v
+	return nil
+}
+
+// ReportingTrigger is the Reporting Triggers IE (TS 29.244 8.2.19).
+type ReportingTrigger uint32
+
+const (
+	REPORTING_TRIGGER_PERIO ReportingTrigger = 1 << iota // Periodic Reporting
+	REPORTING_TRIGGER_VOLTH                              // Volume Threshold
+	REPORTING_TRIGGER_TIMTH                              // Time Threshold
+	REPORTING_TRIGGER_QUHTI                              // Quota Holding Time
+	REPORTING_TRIGGER_START                              // Start of Traffic
+	REPORTING_TRIGGER_STOPT                              // Stop of Traffic
+	REPORTING_TRIGGER_DROTH                              // Dropped DL Traffic Threshold
+	REPORTING_TRIGGER_LIUSA                              // Linked Usage Reporting
+	REPORTING_TRIGGER_VOLQU                              // Volume Quota
+	REPORTING_TRIGGER_TIMQU                              // Time Quota
+	REPORTING_TRIGGER_ENVCL                              // Envelope Closure
+	REPORTING_TRIGGER_MACAR                              // MAC Addresses Reporting
+	REPORTING_TRIGGER_EVETH                              // Event Threshold
+	REPORTING_TRIGGER_EVEQU                              // Event Quota
+	REPORTING_TRIGGER_IPMJL                              // IP Multicast Join/Leave
+	REPORTING_TRIGGER_QUVTI                              // Quota Validity Time
+	REPORTING_TRIGGER_REEMR                              // REport the End Marker Reception
+	REPORTING_TRIGGER_UPINT                              // User Plane Inactivity Timer
+)
+
+var reportingTriggerNames = []flagName[ReportingTrigger]{
+	{REPORTING_TRIGGER_PERIO, "PERIO"},
+	{REPORTING_TRIGGER_VOLTH, "VOLTH"},
+	{REPORTING_TRIGGER_TIMTH, "TIMTH"},
+	{REPORTING_TRIGGER_QUHTI, "QUHTI"},
+	{REPORTING_TRIGGER_START, "START"},
+	{REPORTING_TRIGGER_STOPT, "STOPT"},
+	{REPORTING_TRIGGER_DROTH, "DROTH"},
+	{REPORTING_TRIGGER_LIUSA, "LIUSA"},
+	{REPORTING_TRIGGER_VOLQU, "VOLQU"},
+	{REPORTING_TRIGGER_TIMQU, "TIMQU"},
+	{REPORTING_TRIGGER_ENVCL, "ENVCL"},
+	{REPORTING_TRIGGER_MACAR, "MACAR"},
+	{REPORTING_TRIGGER_EVETH, "EVETH"},
+	{REPORTING_TRIGGER_EVEQU, "EVEQU"},
+	{REPORTING_TRIGGER_IPMJL, "IPMJL"},
+	{REPORTING_TRIGGER_QUVTI, "QUVTI"},
+	{REPORTING_TRIGGER_REEMR, "REEMR"},
+	{REPORTING_TRIGGER_UPINT, "UPINT"},
+}
+
+func (r ReportingTrigger) Has(flag ReportingTrigger) bool {
+	return r&flag == flag
+}
+
+// String returns the set flags joined by "|", e.g. "PERIO|VOLTH".
+func (r ReportingTrigger) String() string {
+	return formatFlags(r, reportingTriggerNames)
+}
+
+// ParseReportingTrigger parses a number or flag names separated by "|" or ",",
+// e.g. "VOLTH" or "perio,volqu".
+func ParseReportingTrigger(s string) (ReportingTrigger, error) {
+	return parseFlags(s, reportingTriggerNames, "reporting trigger")
+}
+
+// MarshalJSON encodes r as a list of flag names.
+func (r ReportingTrigger) MarshalJSON() ([]byte, error) {
+	return marshalFlags(r, reportingTriggerNames)
+}
+
+// UnmarshalJSON accepts a list of flag names or a number.
+func (r *ReportingTrigger) UnmarshalJSON(b []byte) error {
+	v, err := unmarshalFlags(b, reportingTriggerNames, "reporting trigger")
+	if err != nil {
+		return err
+	}
+	*r = v
+	return nil
+}
+
+var urrSpec = attrSpec{
+	name: "URR",
+	attrs: map[int]attrSize{
+		URR_ID:                 {name: "ID", size: 4},
+		URR_MEASUREMENT_METHOD: {name: "MEASUREMENT_METHOD", size: 1},
+		URR_REPORTING_TRIGGER:  {name: "REPORTING_TRIGGER", size: 4},
+		URR_MEASUREMENT_PERIOD: {name: "MEASUREMENT_PERIOD", size: 4},
+		URR_MEASUREMENT_INFO:   {name: "MEASUREMENT_INFO", size: 1},
+		URR_SEID:               {name: "SEID", size: 8},
+		URR_VOLUME_THRESHOLD:   {name: "VOLUME_THRESHOLD"},
+		URR_VOLUME_QUOTA:       {name: "VOLUME_QUOTA"},
+	},
 }
 
 func DecodeURR(b []byte) (*URR, error) {
 	urr := new(URR)
 	for len(b) > 0 {
-		hdr, n, err := nl.DecodeAttrHdr(b)
+		hdr, n, err := urrSpec.decodeAttrHdr(b)
 		if err != nil {
 			return nil, err
 		}
@@ -68,9 +249,9 @@ func DecodeURR(b []byte) (*URR, error) {
 		case URR_ID:
 			urr.ID = native.Uint32(b[n:attrLen])
 		case URR_MEASUREMENT_METHOD:
-			urr.Method = uint8(b[n])
+			urr.Method = MeasurementMethod(b[n])
 		case URR_REPORTING_TRIGGER:
-			urr.Trigger = native.Uint32(b[n:attrLen])
+			urr.Trigger = ReportingTrigger(native.Uint32(b[n:attrLen]))
 		case URR_MEASUREMENT_PERIOD:
 			v := native.Uint32(b[n:attrLen])
 			urr.Period = &v
@@ -81,29 +262,81 @@ func DecodeURR(b []byte) (*URR, error) {
 			v := native.Uint64(b[n:attrLen])
 			urr.SEID = &v
 		case URR_VOLUME_THRESHOLD:
-			volthreshold, err := decodeVolumeThreshold(b[n:attrLen])
+			volthreshold, err := DecodeVolumeThreshold(b[n:attrLen])
 			if err != nil {
-				return nil, err
+				return nil, urrSpec.wrap(err)
 			}
 			urr.VolThreshold = &volthreshold
 		case URR_VOLUME_QUOTA:
-			volumequota, err := decodeVolumeQuota(b[n:attrLen])
+			volumequota, err := DecodeVolumeQuota(b[n:attrLen])
 			if err != nil {
-				return nil, err
+				return nil, urrSpec.wrap(err)
 			}
 			urr.VolQuota = &volumequota
+		default:
+			urr.Unknown = append(urr.Unknown, rawAttr(hdr, b[n:attrLen]))
 		}
 
-		b = b[hdr.Len.Align():]
+		b = nextAttr(b, hdr)
 	}
 	return urr, nil
 }
 
-func decodeVolumeThreshold(b []byte) (VolumeThreshold, error) {
+// EncodeURR returns the attributes of urr for CreateURROID and UpdateURROID.
+// URR_ID and URR_SEID are not included; they are carried by the OID.
+func EncodeURR(urr *URR) []nl.Attr {
+	attrs := []nl.Attr{
+		{
+			Type:  URR_MEASUREMENT_METHOD,
+			Value: nl.AttrU8(urr.Method),
+		},
+		{
+			Type:  URR_REPORTING_TRIGGER,
+			Value: nl.AttrU32(urr.Trigger),
+		},
+	}
+	if urr.Period != nil {
+		attrs = append(attrs, nl.Attr{
+			Type:  URR_MEASUREMENT_PERIOD,
+			Value: nl.AttrU32(*urr.Period),
+		})
+	}
+	if urr.Info != nil {
+		attrs = append(attrs, nl.Attr{
+			Type:  URR_MEASUREMENT_INFO,
+			Value: nl.AttrU8(*urr.Info),
+		})
+	}
+	if urr.VolThreshold != nil {
+		attrs = append(attrs, nl.Attr{
+			Type:  URR_VOLUME_THRESHOLD,
+			Value: nl.AttrList(EncodeVolumeThreshold(*urr.VolThreshold)),
+		})
+	}
+	if urr.VolQuota != nil {
+		attrs = append(attrs, nl.Attr{
+			Type:  URR_VOLUME_QUOTA,
+			Value: nl.AttrList(EncodeVolumeQuota(*urr.VolQuota)),
+		})
+	}
+	return attrs
+}
+
+var volumeThresholdSpec = attrSpec{
+	name: "VOLUME_THRESHOLD",
+	attrs: map[int]attrSize{
+		URR_VOLUME_THRESHOLD_FLAG:  {name: "FLAG", size: 1},
+		URR_VOLUME_THRESHOLD_TOVOL: {name: "TOVOL", size: 8},
+		URR_VOLUME_THRESHOLD_UVOL:  {name: "UVOL", size: 8},
+		URR_VOLUME_THRESHOLD_DVOL:  {name: "DVOL", size: 8},
+	},
+}
+
+func DecodeVolumeThreshold(b []byte) (VolumeThreshold, error) {
 	var volumethreshold VolumeThreshold
 
 	for len(b) > 0 {
-		hdr, n, err := nl.DecodeAttrHdr(b)
+		hdr, n, err := volumeThresholdSpec.decodeAttrHdr(b)
 		if err != nil {
 			return volumethreshold, err
 		}
@@ -111,27 +344,60 @@ func decodeVolumeThreshold(b []byte) (VolumeThreshold, error) {
 		switch hdr.MaskedType() {
 		case URR_VOLUME_THRESHOLD_FLAG:
 			v := uint8(b[n])
-			volumethreshold.flag = v
+			volumethreshold.Flag = v
 		case URR_VOLUME_THRESHOLD_TOVOL:
 			v := native.Uint64(b[n:attrLen])
-			volumethreshold.totalVolume = v
+			volumethreshold.TotalVolume = v
 		case URR_VOLUME_THRESHOLD_UVOL:
 			v := native.Uint64(b[n:attrLen])
-			volumethreshold.uplinkVolume = v
+			volumethreshold.UplinkVolume = v
 		case URR_VOLUME_THRESHOLD_DVOL:
 			v := native.Uint64(b[n:attrLen])
-			volumethreshold.downlinkVolume = v
+			volumethreshold.DownlinkVolume = v
+		default:
+			volumethreshold.Unknown = append(volumethreshold.Unknown, rawAttr(hdr, b[n:attrLen]))
 		}
-		b = b[hdr.Len.Align():]
+		b = nextAttr(b, hdr)
 	}
 	return volumethreshold, nil
 }
 
-func decodeVolumeQuota(b []byte) (VolumeQuota, error) {
+func EncodeVolumeThreshold(volumethreshold VolumeThreshold) []nl.Attr {
+	return []nl.Attr{
+		{
+			Type:  URR_VOLUME_THRESHOLD_FLAG,
+			Value: nl.AttrU8(volumethreshold.Flag),
+		},
+		{
+			Type:  URR_VOLUME_THRESHOLD_TOVOL,
+			Value: nl.AttrU64(volumethreshold.TotalVolume),
+		},
+		{
+			Type:  URR_VOLUME_THRESHOLD_UVOL,
+			Value: nl.AttrU64(volumethreshold.UplinkVolume),
+		},
+		{
+			Type:  URR_VOLUME_THRESHOLD_DVOL,
+			Value: nl.AttrU64(volumethreshold.DownlinkVolume),
+		},
+	}
+}
+
+var volumeQuotaSpec = attrSpec{
+	name: "VOLUME_QUOTA",
+	attrs: map[int]attrSize{
+		URR_VOLUME_QUOTA_FLAG:  {name: "FLAG", size: 1},
+		URR_VOLUME_QUOTA_TOVOL: {name: "TOVOL", size: 8},
+		URR_VOLUME_QUOTA_UVOL:  {name: "UVOL", size: 8},
+		URR_VOLUME_QUOTA_DVOL:  {name: "DVOL", size: 8},
+	},
+}
+
+func DecodeVolumeQuota(b []byte) (VolumeQuota, error) {
 	var volumequota VolumeQuota
 
 	for len(b) > 0 {
-		hdr, n, err := nl.DecodeAttrHdr(b)
+		hdr, n, err := volumeQuotaSpec.decodeAttrHdr(b)
 		if err != nil {
 			return volumequota, err
 		}
@@ -139,18 +405,41 @@ func decodeVolumeQuota(b []byte) (VolumeQuota, error) {
 		switch hdr.MaskedType() {
 		case URR_VOLUME_QUOTA_FLAG:
 			v := uint8(b[n])
-			volumequota.flag = v
+			volumequota.Flag = v
 		case URR_VOLUME_QUOTA_TOVOL:
 			v := native.Uint64(b[n:attrLen])
-			volumequota.totalVolume = v
+			volumequota.TotalVolume = v
 		case URR_VOLUME_QUOTA_UVOL:
 			v := native.Uint64(b[n:attrLen])
-			volumequota.uplinkVolume = v
+			volumequota.UplinkVolume = v
 		case URR_VOLUME_QUOTA_DVOL:
 			v := native.Uint64(b[n:attrLen])
-			volumequota.downlinkVolume = v
+			volumequota.DownlinkVolume = v
+		default:
+			volumequota.Unknown = append(volumequota.Unknown, rawAttr(hdr, b[n:attrLen]))
 		}
-		b = b[hdr.Len.Align():]
+		b = nextAttr(b, hdr)
 	}
 	return volumequota, nil
 }
+
+func EncodeVolumeQuota(volumequota VolumeQuota) []nl.Attr {
+	return []nl.Attr{
+		{
+			Type:  URR_VOLUME_QUOTA_FLAG,
+			Value: nl.AttrU8(volumequota.Flag),
+		},
+		{
+			Type:  URR_VOLUME_QUOTA_TOVOL,
+			Value: nl.AttrU64(volumequota.TotalVolume),
+		},
+		{
+			Type:  URR_VOLUME_QUOTA_UVOL,
+			Value: nl.AttrU64(volumequota.UplinkVolume),
+		},
+		{
+			Type:  URR_VOLUME_QUOTA_DVOL,
+			Value: nl.AttrU64(volumequota.DownlinkVolume),
+		},
+	}
+}
@@ -0,0 +1,132 @@
+package gtp5gnl
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/khirono/go-nl"
+)
+
+func TestEncodeURR(t *testing.T) {
+	seid := uint64(0x1234)
+	period := uint32(60)
+	info := uint8(2)
+
+	cases := []struct {
+		name string
+		urr  URR
+	}{
+		{
+			name: "method",
+			urr: URR{
+				ID:      1,
+				Method:  2,
+				Trigger: 1,
+			},
+		},
+		{
+			name: "volume",
+			urr: URR{
+				ID:      2,
+				Method:  2,
+				Trigger: 3,
+				Period:  &period,
+				Info:    &info,
+				SEID:    &seid,
+				VolThreshold: &VolumeThreshold{
+					Flag:           7,
+					TotalVolume:    1024,
+					UplinkVolume:   2048,
+					DownlinkVolume: 4096,
+				},
+				VolQuota: &VolumeQuota{
+					Flag:           1,
+					TotalVolume:    8192,
+					UplinkVolume:   0,
+					DownlinkVolume: 0,
+				},
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			attrs := []nl.Attr{
+				{
+					Type:  URR_ID,
+					Value: nl.AttrU32(tc.urr.ID),
+				},
+			}
+			if tc.urr.SEID != nil {
+				attrs = append(attrs, nl.Attr{
+					Type:  URR_SEID,
+					Value: nl.AttrU64(*tc.urr.SEID),
+				})
+			}
+			attrs = append(attrs, EncodeURR(&tc.urr)...)
+			urr, err := DecodeURR(encodeAttrs(t, attrs))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(urr, &tc.urr) {
+				t.Errorf("want %+v; but got %+v\n", tc.urr, *urr)
+			}
+		})
+	}
+}
+
+func TestVolumeThresholdFlags(t *testing.T) {
+	var v VolumeThreshold
+	v.SetUplinkVolume(100)
+	v.SetDownlinkVolume(200)
+	if !v.Has(ULVOL | DLVOL) {
+		t.Errorf("flag %#x: want ULVOL|DLVOL", v.Flag)
+	}
+	if v.Has(TOVOL) {
+		t.Errorf("flag %#x: want no TOVOL", v.Flag)
+	}
+
+	j, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"Flag":6,"TotalVolume":0,"UplinkVolume":100,"DownlinkVolume":200}`
+	if string(j) != want {
+		t.Errorf("want %s; but got %s", want, j)
+	}
+}
+
+func TestReportingTrigger(t *testing.T) {
+	tr, err := ParseReportingTrigger("PERIO|VOLQU")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tr != REPORTING_TRIGGER_PERIO|REPORTING_TRIGGER_VOLQU || uint32(tr) != 0x101 {
+		t.Errorf("want PERIO|VOLQU (0x101); but got %v", tr)
+	}
+	_, err = ParseReportingTrigger("PERIO,IMMER")
+	if err == nil {
+		t.Error("want error for a usage report trigger")
+	}
+
+	ur := USAGE_REPORT_TRIGGER_IMMER | USAGE_REPORT_TRIGGER_TERMR
+	if ur.String() != "IMMER|TERMR" {
+		t.Errorf("want IMMER|TERMR; but got %v", ur)
+	}
+
+	j, err := json.Marshal(URR{Method: MEASUREMENT_METHOD_VOLUM | MEASUREMENT_METHOD_DURAT, Trigger: tr})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var urr URR
+	err = json.Unmarshal(j, &urr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if urr.Method.String() != "DURAT|VOLUM" || urr.Trigger != tr {
+		t.Errorf("JSON %s decoded to %v, %v", j, urr.Method, urr.Trigger)
+	}
+	if MeasurementMethod(0).String() != "0" {
+		t.Errorf("want 0; but got %v", MeasurementMethod(0))
+	}
+}
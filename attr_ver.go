@@ -2,12 +2,12 @@ package gtp5gnl
 
 import (
 	"bytes"
-
-	"github.com/khirono/go-nl"
 )
 
+var versionSpec = attrSpec{name: "VERSION"}
+
 func DecodeVersion(b []byte) (string, error) {
-	hdr, n, err := nl.DecodeAttrHdr(b)
+	hdr, n, err := versionSpec.decodeAttrHdr(b)
 	if err != nil {
 		return "", err
 	}
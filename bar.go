@@ -1,6 +1,7 @@
 package gtp5gnl
 
 import (
+	"context"
 	"fmt"
 	"syscall"
 
@@ -18,9 +19,13 @@ func CreateBAR(c *Client, link *Link, barid int, attrs []nl.Attr) error {
 }
 
 func CreateBAROID(c *Client, link *Link, oid OID, attrs []nl.Attr) error {
+	return CreateBAROIDContext(context.Background(), c, link, oid, attrs)
+}
+
+func CreateBAROIDContext(ctx context.Context, c *Client, link *Link, oid OID, attrs []nl.Attr) error {
 	flags := syscall.NLM_F_EXCL
 	flags |= syscall.NLM_F_ACK
-	req := nl.NewRequest(c.ID, flags)
+	req := nl.NewRequest(c.familyID(), flags)
 	err := req.Append(genl.Header{Cmd: CMD_ADD_BAR})
 	if err != nil {
 		return err
@@ -56,8 +61,8 @@ func CreateBAROID(c *Client, link *Link, oid OID, attrs []nl.Attr) error {
 	if err != nil {
 		return err
 	}
-	_, err = c.Do(req)
-	return err
+	_, err = c.DoContext(ctx, req)
+	return withOID(err, oid)
 }
 
 func UpdateBAR(c *Client, link *Link, barid int, attrs []nl.Attr) error {
@@ -65,9 +70,13 @@ func UpdateBAR(c *Client, link *Link, barid int, attrs []nl.Attr) error {
 }
 
 func UpdateBAROID(c *Client, link *Link, oid OID, attrs []nl.Attr) error {
+	return UpdateBAROIDContext(context.Background(), c, link, oid, attrs)
+}
+
+func UpdateBAROIDContext(ctx context.Context, c *Client, link *Link, oid OID, attrs []nl.Attr) error {
 	flags := syscall.NLM_F_REPLACE
 	flags |= syscall.NLM_F_ACK
-	req := nl.NewRequest(c.ID, flags)
+	req := nl.NewRequest(c.familyID(), flags)
 	err := req.Append(genl.Header{Cmd: CMD_ADD_BAR})
 	if err != nil {
 		return err
@@ -103,8 +112,8 @@ func UpdateBAROID(c *Client, link *Link, oid OID, attrs []nl.Attr) error {
 	if err != nil {
 		return err
 	}
-	_, err = c.Do(req)
-	return err
+	_, err = c.DoContext(ctx, req)
+	return withOID(err, oid)
 }
 
 func RemoveBAR(c *Client, link *Link, barid int) error {
@@ -112,9 +121,13 @@ func RemoveBAR(c *Client, link *Link, barid int) error {
 }
 
 func RemoveBAROID(c *Client, link *Link, oid OID) error {
+	return RemoveBAROIDContext(context.Background(), c, link, oid)
+}
+
+func RemoveBAROIDContext(ctx context.Context, c *Client, link *Link, oid OID) error {
 	flags := syscall.NLM_F_EXCL
 	flags |= syscall.NLM_F_ACK
-	req := nl.NewRequest(c.ID, flags)
+	req := nl.NewRequest(c.familyID(), flags)
 	err := req.Append(genl.Header{Cmd: CMD_DEL_BAR})
 	if err != nil {
 		return err
@@ -146,8 +159,8 @@ func RemoveBAROID(c *Client, link *Link, oid OID) error {
 			return err
 		}
 	}
-	_, err = c.Do(req)
-	return err
+	_, err = c.DoContext(ctx, req)
+	return withOID(err, oid)
 }
 
 func GetBAR(c *Client, link *Link, barid int) (*BAR, error) {
@@ -155,8 +168,12 @@ func GetBAR(c *Client, link *Link, barid int) (*BAR, error) {
 }
 
 func GetBAROID(c *Client, link *Link, oid OID) (*BAR, error) {
+	return GetBAROIDContext(context.Background(), c, link, oid)
+}
+
+func GetBAROIDContext(ctx context.Context, c *Client, link *Link, oid OID) (*BAR, error) {
 	flags := syscall.NLM_F_ACK
-	req := nl.NewRequest(c.ID, flags)
+	req := nl.NewRequest(c.familyID(), flags)
 	err := req.Append(genl.Header{Cmd: CMD_GET_BAR})
 	if err != nil {
 		return nil, err
@@ -188,12 +205,12 @@ func GetBAROID(c *Client, link *Link, oid OID) (*BAR, error) {
 			return nil, err
 		}
 	}
-	rsps, err := c.Do(req)
+	rsps, err := c.DoContext(ctx, req)
 	if err != nil {
-		return nil, err
+		return nil, withOID(err, oid)
 	}
 	if len(rsps) < 1 {
-		return nil, fmt.Errorf("nil BAR of oid(%v)", oid)
+		return nil, notFound(CMD_GET_BAR, oid)
 	}
 	bar, err := DecodeBAR(rsps[0].Body[genl.SizeofHeader:])
 	if err != nil {
@@ -203,13 +220,17 @@ func GetBAROID(c *Client, link *Link, oid OID) (*BAR, error) {
 }
 
 func GetBARAll(c *Client) ([]BAR, error) {
+	return GetBARAllContext(context.Background(), c)
+}
+
+func GetBARAllContext(ctx context.Context, c *Client) ([]BAR, error) {
 	flags := syscall.NLM_F_DUMP
-	req := nl.NewRequest(c.ID, flags)
+	req := nl.NewRequest(c.familyID(), flags)
 	err := req.Append(genl.Header{Cmd: CMD_GET_BAR})
 	if err != nil {
 		return nil, err
 	}
-	rsps, err := c.Do(req)
+	rsps, err := c.DoContext(ctx, req)
 	if err != nil {
 		return nil, err
 	}
@@ -3,8 +3,6 @@ package gtp5gnl
 import (
 	"encoding/json"
 	"log"
-	"sync"
-	"syscall"
 	"testing"
 
 	"github.com/khirono/go-nl"
@@ -15,31 +13,11 @@ func TestCreateBAR(t *testing.T) {
 		t.Skip("skipping testing in short mode")
 	}
 
-	var wg sync.WaitGroup
-	mux, err := nl.NewMux()
-	if err != nil {
-		t.Fatal(err)
-	}
-	defer func() {
-		mux.Close()
-		wg.Wait()
-	}()
-	wg.Add(1)
-	go func() {
-		mux.Serve()
-		wg.Done()
-	}()
-
-	conn, err := nl.Open(syscall.NETLINK_GENERIC)
-	if err != nil {
-		t.Fatal(err)
-	}
-	defer conn.Close()
-
-	c, err := NewClient(conn, mux)
+	c, err := Dial()
 	if err != nil {
 		t.Fatal(err)
 	}
+	defer c.Close()
 
 	link, err := GetLink("upfgtp")
 	if err != nil {
@@ -68,31 +46,11 @@ func TestGetBAR(t *testing.T) {
 		t.Skip("skipping testing in short mode")
 	}
 
-	var wg sync.WaitGroup
-	mux, err := nl.NewMux()
-	if err != nil {
-		t.Fatal(err)
-	}
-	defer func() {
-		mux.Close()
-		wg.Wait()
-	}()
-	wg.Add(1)
-	go func() {
-		mux.Serve()
-		wg.Done()
-	}()
-
-	conn, err := nl.Open(syscall.NETLINK_GENERIC)
-	if err != nil {
-		t.Fatal(err)
-	}
-	defer conn.Close()
-
-	c, err := NewClient(conn, mux)
+	c, err := Dial()
 	if err != nil {
 		t.Fatal(err)
 	}
+	defer c.Close()
 
 	link, err := GetLink("upfgtp")
 	if err != nil {
@@ -116,31 +74,11 @@ func TestGetBARAll(t *testing.T) {
 		t.Skip("skipping testing in short mode")
 	}
 
-	var wg sync.WaitGroup
-	mux, err := nl.NewMux()
-	if err != nil {
-		t.Fatal(err)
-	}
-	defer func() {
-		mux.Close()
-		wg.Wait()
-	}()
-	wg.Add(1)
-	go func() {
-		mux.Serve()
-		wg.Done()
-	}()
-
-	conn, err := nl.Open(syscall.NETLINK_GENERIC)
-	if err != nil {
-		t.Fatal(err)
-	}
-	defer conn.Close()
-
-	c, err := NewClient(conn, mux)
+	c, err := Dial()
 	if err != nil {
 		t.Fatal(err)
 	}
+	defer c.Close()
 
 	fars, err := GetBARAll(c)
 	if err != nil {
@@ -159,31 +97,11 @@ func TestRemoveBAR(t *testing.T) {
 		t.Skip("skipping testing in short mode")
 	}
 
-	var wg sync.WaitGroup
-	mux, err := nl.NewMux()
-	if err != nil {
-		t.Fatal(err)
-	}
-	defer func() {
-		mux.Close()
-		wg.Wait()
-	}()
-	wg.Add(1)
-	go func() {
-		mux.Serve()
-		wg.Done()
-	}()
-
-	conn, err := nl.Open(syscall.NETLINK_GENERIC)
-	if err != nil {
-		t.Fatal(err)
-	}
-	defer conn.Close()
-
-	c, err := NewClient(conn, mux)
+	c, err := Dial()
 	if err != nil {
 		t.Fatal(err)
 	}
+	defer c.Close()
 
 	link, err := GetLink("upfgtp")
 	if err != nil {
@@ -0,0 +1,121 @@
+package gtp5gnl
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// BitRate is a bit rate in kbps as carried by the MBR and GBR IEs
+// (TS 29.244 8.2.8, 8.2.9). It is at most 40 bits wide.
+type BitRate uint64
+
+const (
+	Kbps BitRate = 1
+	Mbps         = 1000 * Kbps
+	Gbps         = 1000 * Mbps
+	Tbps         = 1000 * Gbps
+
+	MaxBitRate BitRate = 1<<40 - 1
+)
+
+var bitRateUnits = []struct {
+	unit BitRate
+	name string
+}{
+	{Tbps, "Tbps"},
+	{Gbps, "Gbps"},
+	{Mbps, "Mbps"},
+	{Kbps, "kbps"},
+}
+
+// BitRateOf joins the HIGH32/LOW8 pair of an MBR or GBR.
+func BitRateOf(high uint32, low uint8) BitRate {
+	return BitRate(high)<<8 | BitRate(low)
+}
+
+// Split returns the HIGH32/LOW8 pair of r.
+func (r BitRate) Split() (high uint32, low uint8) {
+	return uint32(r >> 8), uint8(r)
+}
+
+// String formats r in the largest unit it fills, e.g. "64kbps", "150Mbps"
+// or "1.5Gbps".
+func (r BitRate) String() string {
+	for _, u := range bitRateUnits {
+		if r >= u.unit || u.unit == Kbps {
+			v := float64(r) / float64(u.unit)
+			return strconv.FormatFloat(v, 'f', -1, 64) + u.name
+		}
+	}
+	return ""
+}
+
+// ParseBitRate parses a number followed by one of the units bps, kbps,
+// Mbps, Gbps or Tbps, e.g. "150Mbps" or "1.5Gbps". The unit prefix is
+// case-insensitive. A plain number is in kbps. The rate is rounded to kbps.
+func ParseBitRate(s string) (BitRate, error) {
+	s = strings.TrimSpace(s)
+	if v, err := strconv.ParseUint(s, 0, 40); err == nil {
+		return BitRate(v), nil
+	}
+	i := strings.IndexFunc(s, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	if i <= 0 {
+		return 0, fmt.Errorf("invalid bit rate %q", s)
+	}
+	v, err := strconv.ParseFloat(s[:i], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid bit rate %q", s)
+	}
+	var scale float64
+	switch strings.ToLower(strings.TrimSpace(s[i:])) {
+	case "bps":
+		scale = 1e-3
+	case "kbps":
+		scale = 1
+	case "mbps":
+		scale = 1e3
+	case "gbps":
+		scale = 1e6
+	case "tbps":
+		scale = 1e9
+	default:
+		return 0, fmt.Errorf("invalid bit rate unit %q", s[i:])
+	}
+	kbps := math.Round(v * scale)
+	if kbps > float64(MaxBitRate) {
+		return 0, fmt.Errorf("bit rate %q out of range", s)
+	}
+	return BitRate(kbps), nil
+}
+
+// MarshalJSON encodes r as a string, e.g. "150Mbps".
+func (r BitRate) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.String())
+}
+
+// UnmarshalJSON accepts a string for ParseBitRate or a number in kbps.
+func (r *BitRate) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err == nil {
+		v, err := ParseBitRate(s)
+		if err != nil {
+			return err
+		}
+		*r = v
+		return nil
+	}
+	var v uint64
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	if v > uint64(MaxBitRate) {
+		return fmt.Errorf("bit rate %v out of range", v)
+	}
+	*r = BitRate(v)
+	return nil
+}
@@ -0,0 +1,77 @@
+package gtp5gnl
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestBitRate(t *testing.T) {
+	tests := []struct {
+		s    string
+		rate BitRate
+		str  string
+	}{
+		{"64kbps", 64, "64kbps"},
+		{"150Mbps", 150000, "150Mbps"},
+		{"1.5Gbps", 1500000, "1.5Gbps"},
+		{"1.5gbps", 1500000, "1.5Gbps"},
+		{"2Tbps", 2000000000, "2Tbps"},
+		{"64000bps", 64, "64kbps"},
+		{"1000", 1000, "1Mbps"},
+		{"1234", 1234, "1.234Mbps"},
+		{"0", 0, "0kbps"},
+	}
+	for _, tt := range tests {
+		r, err := ParseBitRate(tt.s)
+		if err != nil {
+			t.Errorf("ParseBitRate(%q): %v", tt.s, err)
+			continue
+		}
+		if r != tt.rate {
+			t.Errorf("ParseBitRate(%q) = %d; want %d", tt.s, uint64(r), uint64(tt.rate))
+		}
+		if r.String() != tt.str {
+			t.Errorf("%d: want %v; but got %v", uint64(r), tt.str, r)
+		}
+	}
+	for _, s := range []string{"", "Mbps", "10Xbps", "2000Pbps", "1e3kbps"} {
+		_, err := ParseBitRate(s)
+		if err == nil {
+			t.Errorf("ParseBitRate(%q): want error", s)
+		}
+	}
+}
+
+func TestBitRateSplit(t *testing.T) {
+	r := BitRate(0x1234567890)
+	high, low := r.Split()
+	if high != 0x12345678 || low != 0x90 {
+		t.Errorf("want 0x12345678, 0x90; but got %#x, %#x", high, low)
+	}
+	if BitRateOf(high, low) != r {
+		t.Errorf("want %v; but got %v", r, BitRateOf(high, low))
+	}
+	mbr := NewMBR(150*Mbps, 1500*Mbps)
+	if mbr.DLHigh != uint32(1500000>>8) || mbr.DLLow != uint8(1500000&0xff) {
+		t.Errorf("unexpected DL HIGH32/LOW8 %#x/%#x", mbr.DLHigh, mbr.DLLow)
+	}
+}
+
+func TestBitRateJSON(t *testing.T) {
+	j, err := json.Marshal(NewGBR(64*Kbps, 150*Mbps))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"ULHigh":0,"ULLow":64,"UL_Kbps":"64kbps","DLHigh":585,"DLLow":240,"DL_Kbps":"150Mbps"}`
+	if string(j) != want {
+		t.Errorf("want %s; but got %s", want, j)
+	}
+	var rates []BitRate
+	err = json.Unmarshal([]byte(`["1.5Gbps", 64]`), &rates)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rates) != 2 || rates[0] != 1500*Mbps || rates[1] != 64*Kbps {
+		t.Errorf("unexpected rates %v", rates)
+	}
+}
@@ -0,0 +1,360 @@
+package gtp5gnl
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"syscall"
+
+	"github.com/khirono/go-genl"
+	"github.com/khirono/go-nl"
+)
+
+// BufferedPacket is a downlink packet the kernel buffered for a PDR whose
+// FAR has the BUFF or NOCP action, as sent to PDR_UNIX_SOCKET_PATH.
+type BufferedPacket struct {
+	PDRID     uint16
+	SEID      uint64
+	Action    ApplyAction
+	SeqNumber uint32
+	// SeqBits is the width of SeqNumber as sent by the module, 16 or 32;
+	// 0 is taken as 32.
+	SeqBits int
+	Packet  []byte
+	Unknown []RawAttr `json:",omitempty"`
+}
+
+// OID returns the OID of the PDR of p.
+func (p *BufferedPacket) OID() OID {
+	return OID{p.SEID, uint64(p.PDRID)}
+}
+
+// BUFFER_SEQ_NUMBER is a u16 in older modules and a u32 since; the spec
+// holds the smaller size and DecodeBufferedPacket refuses the others.
+var bufferedPacketSpec = attrSpec{
+	name: "BUFFER",
+	attrs: map[int]attrSize{
+		BUFFER_ID:         {name: "ID", size: 2},
+		BUFFER_SEID:       {name: "SEID", size: 8},
+		BUFFER_ACTION:     {name: "ACTION", size: 2},
+		BUFFER_SEQ_NUMBER: {name: "SEQ_NUMBER", size: 2},
+	},
+}
+
+// DecodeBufferedPacket decodes the attributes of a BUFFER message. The
+// packet is copied from b.
+func DecodeBufferedPacket(b []byte) (*BufferedPacket, error) {
+	p := new(BufferedPacket)
+	for len(b) > 0 {
+		hdr, n, err := bufferedPacketSpec.decodeAttrHdr(b)
+		if err != nil {
+			return nil, err
+		}
+		attrLen := int(hdr.Len)
+		switch hdr.MaskedType() {
+		case BUFFER_PAD:
+		case BUFFER_PACKET:
+			p.Packet = append([]byte(nil), b[n:attrLen]...)
+		case BUFFER_ID:
+			p.PDRID = native.Uint16(b[n:attrLen])
+		case BUFFER_SEID:
+			p.SEID = native.Uint64(b[n:attrLen])
+		case BUFFER_ACTION:
+			p.Action = ApplyAction(native.Uint16(b[n:attrLen]))
+		case BUFFER_SEQ_NUMBER:
+			switch attrLen - n {
+			case 2:
+				p.SeqNumber = uint32(native.Uint16(b[n:attrLen]))
+				p.SeqBits = 16
+			case 4:
+				p.SeqNumber = native.Uint32(b[n:attrLen])
+				p.SeqBits = 32
+			default:
+				return nil, &DecodeError{Path: "BUFFER.SEQ_NUMBER", Len: attrLen - n, Want: 4}
+			}
+		default:
+			p.Unknown = append(p.Unknown, rawAttr(hdr, b[n:attrLen]))
+		}
+		b = nextAttr(b, hdr)
+	}
+	return p, nil
+}
+
+// BufferListener receives the packets the kernel buffers for PDRs created
+// with PDR_UNIX_SOCKET_PATH set to its path.
+type BufferListener struct {
+	conn    *net.UnixConn
+	path    string
+	ch      chan BufferedPacket
+	done    chan struct{}
+	once    sync.Once
+	err     error
+	dropped atomic.Uint64
+}
+
+// ListenBuffer binds an AF_UNIX datagram socket at path, removing a stale
+// socket file left there, and starts delivering the buffered packets sent
+// to it on Packets.
+func ListenBuffer(path string) (*BufferListener, error) {
+	err := os.Remove(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		return nil, err
+	}
+	l := &BufferListener{
+		conn: conn,
+		path: path,
+		ch:   make(chan BufferedPacket),
+		done: make(chan struct{}),
+	}
+	go l.serve()
+	return l, nil
+}
+
+// Packets returns the channel of buffered packets. It is closed after
+// Close or when reading the socket fails; see Err.
+func (l *BufferListener) Packets() <-chan BufferedPacket {
+	return l.ch
+}
+
+// Err returns the error that closed Packets, or nil if it is open or was
+// closed by Close.
+func (l *BufferListener) Err() error {
+	select {
+	case <-l.done:
+		return l.err
+	default:
+		return nil
+	}
+}
+
+// Dropped returns the number of messages that were skipped because they
+// could not be decoded.
+func (l *BufferListener) Dropped() uint64 {
+	return l.dropped.Load()
+}
+
+// Addr returns the path of the socket.
+func (l *BufferListener) Addr() string {
+	return l.path
+}
+
+// Close closes the socket, removes its file and waits until Packets is
+// closed. Packets not yet received are discarded.
+func (l *BufferListener) Close() error {
+	var err error
+	l.once.Do(func() {
+		err = l.conn.Close()
+		for range l.ch {
+		}
+		os.Remove(l.path)
+	})
+	return err
+}
+
+func (l *BufferListener) serve() {
+	defer close(l.done)
+	defer close(l.ch)
+	b := make([]byte, 96*1024)
+	for {
+		n, _, err := l.conn.ReadFrom(b)
+		if err != nil {
+			if errors.Is(err, syscall.EINTR) {
+				continue
+			}
+			if !errors.Is(err, net.ErrClosed) {
+				l.err = err
+			}
+			return
+		}
+		p, ok, err := decodeBufferMsg(b[:n])
+		if err != nil {
+			l.dropped.Add(1)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		l.ch <- *p
+	}
+}
+
+// decodeBufferMsg decodes a message from the socket of a BufferListener.
+// Each message starts with a netlink header whose type is BUFFER or REPORT;
+// it reports false for the latter, which is not a buffered packet.
+func decodeBufferMsg(b []byte) (*BufferedPacket, bool, error) {
+	if len(b) < syscall.SizeofNlMsghdr {
+		return nil, false, &DecodeError{Path: "BUFFER", Len: len(b), Want: syscall.SizeofNlMsghdr}
+	}
+	switch n := int(native.Uint32(b)); {
+	case n < syscall.SizeofNlMsghdr:
+		return nil, false, &DecodeError{Path: "BUFFER", Len: n, Want: syscall.SizeofNlMsghdr}
+	case n > len(b):
+		return nil, false, &DecodeError{Path: "BUFFER", Len: len(b), Want: n}
+	}
+	msg, _, err := nl.DecodeMsg(b)
+	if err != nil {
+		return nil, false, err
+	}
+	if msg.Header.Type != BUFFER {
+		return nil, false, nil
+	}
+	p, err := DecodeBufferedPacket(msg.Body)
+	if err != nil {
+		return nil, false, err
+	}
+	return p, true, nil
+}
+
+// m LINK: ifindex
+// o BUFFER_SEID: u64
+// m BUFFER_ID: u16
+// m BUFFER_PACKET: packet
+func SendBufferedPacket(c *Client, link *Link, oid OID, pkt []byte) error {
+	return SendBufferedPacketContext(context.Background(), c, link, oid, pkt)
+}
+
+// SendBufferedPacketContext hands pkt, a packet received by a
+// BufferListener, back to the kernel with CMD_BUFFER_GTPU, which forwards
+// it by the FAR of the PDR of oid.
+func SendBufferedPacketContext(ctx context.Context, c *Client, link *Link, oid OID, pkt []byte) error {
+	err := c.require("CMD_BUFFER_GTPU", c.Capabilities().BufferGTPU)
+	if err != nil {
+		return err
+	}
+	flags := syscall.NLM_F_ACK
+	req := nl.NewRequest(c.familyID(), flags)
+	err = req.Append(genl.Header{Cmd: CMD_BUFFER_GTPU})
+	if err != nil {
+		return err
+	}
+	pdrid, ok := oid.ID()
+	if !ok {
+		return fmt.Errorf("invalid oid: %v", oid)
+	}
+	err = req.Append(nl.AttrList{
+		{
+			Type:  LINK,
+			Value: nl.AttrU32(link.Index),
+		},
+		{
+			Type:  BUFFER_ID,
+			Value: nl.AttrU16(pdrid),
+		},
+	})
+	if err != nil {
+		return err
+	}
+	seid, ok := oid.SEID()
+	if ok {
+		err = req.Append(&nl.Attr{
+			Type:  BUFFER_SEID,
+			Value: nl.AttrU64(seid),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	err = req.Append(&nl.Attr{
+		Type:  BUFFER_PACKET,
+		Value: nl.AttrBytes(pkt),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = c.DoContext(ctx, req)
+	return withOID(err, oid)
+}
+
+func DrainBufferedPackets(c *Client, link *Link, pkts []BufferedPacket) ([]BufferedPacket, error) {
+	return DrainBufferedPacketsContext(context.Background(), c, link, pkts)
+}
+
+// DrainBufferedPacketsContext sends pkts with SendBufferedPacketContext
+// PDR by PDR, in the order the PDRs first appear in pkts, and the packets
+// of each PDR in the order of their sequence numbers; packets with equal
+// numbers keep their order in pkts. It stops at the first failure and returns the
+// packets not sent, in sending order, so that they can be retried or
+// dropped. pkts is not modified.
+func DrainBufferedPacketsContext(ctx context.Context, c *Client, link *Link, pkts []BufferedPacket) ([]BufferedPacket, error) {
+	pkts = slices.Clone(pkts)
+	sortBySeqNumber(pkts)
+	for i := range pkts {
+		err := SendBufferedPacketContext(ctx, c, link, pkts[i].OID(), pkts[i].Packet)
+		if err != nil {
+			return pkts[i:], err
+		}
+	}
+	return nil, nil
+}
+
+// seqMask returns the mask of the bits of p.SeqNumber.
+func (p *BufferedPacket) seqMask() uint32 {
+	if p.SeqBits <= 0 || p.SeqBits >= 32 {
+		return 1<<32 - 1
+	}
+	return 1<<p.SeqBits - 1
+}
+
+// sortBySeqNumber groups pkts by PDR, in the order the PDRs first appear,
+// and sorts the packets of each PDR by sequence number. The numbers of a
+// PDR are unrelated to those of another one and wrap around at their
+// width, so each PDR counts from its own base: the number that follows
+// the largest gap between its numbers, which is the oldest one as long as
+// they span less than half of the range.
+func sortBySeqNumber(pkts []BufferedPacket) {
+	type pdrKey struct {
+		seid uint64
+		id   uint16
+	}
+	type pdrSeqs struct {
+		order int
+		mask  uint32
+		base  uint32
+		seqs  []uint32
+	}
+	pdrs := make(map[pdrKey]*pdrSeqs)
+	for i := range pkts {
+		k := pdrKey{pkts[i].SEID, pkts[i].PDRID}
+		s := pdrs[k]
+		if s == nil {
+			s = &pdrSeqs{order: len(pdrs), mask: pkts[i].seqMask()}
+			pdrs[k] = s
+		}
+		s.seqs = append(s.seqs, pkts[i].SeqNumber&s.mask)
+	}
+	for _, s := range pdrs {
+		s.base = seqBase(s.seqs, s.mask)
+	}
+	slices.SortStableFunc(pkts, func(a, b BufferedPacket) int {
+		sa := pdrs[pdrKey{a.SEID, a.PDRID}]
+		sb := pdrs[pdrKey{b.SEID, b.PDRID}]
+		return cmp.Or(
+			cmp.Compare(sa.order, sb.order),
+			cmp.Compare((a.SeqNumber-sa.base)&sa.mask, (b.SeqNumber-sb.base)&sb.mask),
+		)
+	})
+}
+
+// seqBase returns the number in seqs that follows the largest gap between
+// them, going around the range of numbers masked by mask.
+func seqBase(seqs []uint32, mask uint32) uint32 {
+	slices.Sort(seqs)
+	base := seqs[0]
+	gap := uint64(seqs[0]) + uint64(mask) + 1 - uint64(seqs[len(seqs)-1])
+	for i := 1; i < len(seqs); i++ {
+		if g := uint64(seqs[i] - seqs[i-1]); g > gap {
+			base, gap = seqs[i], g
+		}
+	}
+	return base
+}
@@ -0,0 +1,212 @@
+package gtp5gnl
+
+import (
+	"context"
+	"errors"
+	"net"
+	"path/filepath"
+	"reflect"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/khirono/go-nl"
+)
+
+// bufferMsg frames attrs like a message the kernel sends to the socket of
+// a BufferListener.
+func bufferMsg(t testing.TB, typ uint16, attrs []nl.Attr) []byte {
+	t.Helper()
+	body := encodeAttrs(t, attrs)
+	b := make([]byte, syscall.SizeofNlMsghdr, syscall.SizeofNlMsghdr+len(body))
+	native.PutUint32(b[0:4], uint32(cap(b)))
+	native.PutUint16(b[4:6], typ)
+	return append(b, body...)
+}
+
+func bufferAttrs(pdrid uint16, seq uint32, pkt []byte) []nl.Attr {
+	return []nl.Attr{
+		{Type: BUFFER_PACKET, Value: nl.AttrBytes(pkt)},
+		{Type: BUFFER_ID, Value: nl.AttrU16(pdrid)},
+		{Type: BUFFER_SEID, Value: nl.AttrU64(0x1234)},
+		{Type: BUFFER_ACTION, Value: nl.AttrU16(APPLY_ACTION_BUFF | APPLY_ACTION_NOCP)},
+		{Type: BUFFER_SEQ_NUMBER, Value: nl.AttrU32(seq)},
+	}
+}
+
+func TestDecodeBufferedPacket(t *testing.T) {
+	pkt := []byte{0x45, 0, 0, 20, 1, 2, 3}
+	p, err := DecodeBufferedPacket(encodeAttrs(t, bufferAttrs(3, 7, pkt)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &BufferedPacket{
+		PDRID:     3,
+		SEID:      0x1234,
+		Action:    APPLY_ACTION_BUFF | APPLY_ACTION_NOCP,
+		SeqNumber: 7,
+		SeqBits:   32,
+		Packet:    pkt,
+	}
+	if !reflect.DeepEqual(p, want) {
+		t.Errorf("want %+v; but got %+v", want, p)
+	}
+	if oid := p.OID(); !oid.Equal(OID{0x1234, 3}) {
+		t.Errorf("want oid(0x1234, 3), got %v", oid)
+	}
+
+	// u16 sequence number
+	p, err = DecodeBufferedPacket(encodeAttrs(t, []nl.Attr{
+		{Type: BUFFER_SEQ_NUMBER, Value: nl.AttrU16(9)},
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.SeqNumber != 9 || p.SeqBits != 16 {
+		t.Errorf("want u16 SeqNumber 9, got %v (%v bits)", p.SeqNumber, p.SeqBits)
+	}
+
+	_, err = DecodeBufferedPacket(encodeAttrs(t, []nl.Attr{
+		{Type: BUFFER_SEQ_NUMBER, Value: nl.AttrBytes{1, 2, 3}},
+	}))
+	wantErr := &DecodeError{Path: "BUFFER.SEQ_NUMBER", Len: 3, Want: 4}
+	var e *DecodeError
+	if !errors.As(err, &e) || *e != *wantErr {
+		t.Errorf("want %v, got %v", wantErr, err)
+	}
+}
+
+func TestDecodeBufferMsg(t *testing.T) {
+	msg := bufferMsg(t, BUFFER, bufferAttrs(1, 1, []byte{1}))
+	p, ok, err := decodeBufferMsg(msg)
+	if err != nil || !ok || p.PDRID != 1 {
+		t.Errorf("got %+v, %v, %v", p, ok, err)
+	}
+
+	_, ok, err = decodeBufferMsg(bufferMsg(t, REPORT, nil))
+	if err != nil || ok {
+		t.Errorf("REPORT: got %v, %v", ok, err)
+	}
+
+	var derr *DecodeError
+	for _, b := range [][]byte{msg[:8], msg[:len(msg)-1]} {
+		_, _, err = decodeBufferMsg(b)
+		if !errors.As(err, &derr) {
+			t.Errorf("%d bytes: want *DecodeError, got %v", len(b), err)
+		}
+	}
+}
+
+func TestBufferListener(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "buffer.sock")
+	l, err := ListenBuffer(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	for _, b := range [][]byte{
+		bufferMsg(t, BUFFER, bufferAttrs(1, 1, []byte{1})),
+		{1, 2, 3},
+		bufferMsg(t, REPORT, nil),
+		bufferMsg(t, BUFFER, bufferAttrs(1, 2, []byte{2})),
+	} {
+		_, err = conn.Write(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for seq := uint32(1); seq <= 2; seq++ {
+		select {
+		case p := <-l.Packets():
+			if p.SeqNumber != seq {
+				t.Errorf("want SeqNumber %v, got %v", seq, p.SeqNumber)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("timeout")
+		}
+	}
+	if n := l.Dropped(); n != 1 {
+		t.Errorf("want 1 dropped message, got %v", n)
+	}
+
+	l.Close()
+	if _, ok := <-l.Packets(); ok {
+		t.Error("Packets not closed")
+	}
+	if err := l.Err(); err != nil {
+		t.Errorf("want nil Err after Close, got %v", err)
+	}
+}
+
+func TestSortBySeqNumber(t *testing.T) {
+	pkts := []BufferedPacket{
+		{PDRID: 1, SeqNumber: 2},
+		{PDRID: 1, SeqNumber: 0xffffffff},
+		{PDRID: 2, SeqNumber: 1},
+		{PDRID: 1, SeqNumber: 1},
+		{PDRID: 1, SeqNumber: 0},
+	}
+	sortBySeqNumber(pkts)
+	var got [][2]uint32
+	for _, p := range pkts {
+		got = append(got, [2]uint32{uint32(p.PDRID), p.SeqNumber})
+	}
+	want := [][2]uint32{{1, 0xffffffff}, {1, 0}, {1, 1}, {1, 2}, {2, 1}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("want %v; but got %v", want, got)
+	}
+}
+
+func TestSortBySeqNumberU16(t *testing.T) {
+	pkts := []BufferedPacket{
+		{PDRID: 1, SeqNumber: 1, SeqBits: 16},
+		{PDRID: 1, SeqNumber: 0xffff, SeqBits: 16},
+		{PDRID: 1, SeqNumber: 0, SeqBits: 16},
+		{PDRID: 1, SeqNumber: 0xfffe, SeqBits: 16},
+		// same numbers, another PDR
+		{PDRID: 2, SeqNumber: 0, SeqBits: 16},
+		{PDRID: 2, SeqNumber: 0xffff, SeqBits: 16},
+	}
+	sortBySeqNumber(pkts)
+	var got [][2]uint32
+	for _, p := range pkts {
+		got = append(got, [2]uint32{uint32(p.PDRID), p.SeqNumber})
+	}
+	want := [][2]uint32{{1, 0xfffe}, {1, 0xffff}, {1, 0}, {1, 1}, {2, 0xffff}, {2, 0}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("want %v; but got %v", want, got)
+	}
+}
+
+func TestDrainBufferedPacketsCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	c := &Client{sem: make(chan struct{}, 1)}
+	link := &Link{Name: "upfgtp", Index: 1}
+	pkts := []BufferedPacket{
+		{PDRID: 1, SEID: 1, SeqNumber: 2},
+		{PDRID: 1, SEID: 1, SeqNumber: 1},
+	}
+	left, err := DrainBufferedPacketsContext(ctx, c, link, pkts)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("want context.Canceled, got %v", err)
+	}
+	var e *Error
+	if !errors.As(err, &e) || e.Cmd != CMD_BUFFER_GTPU || !e.OID.Equal(OID{1, 1}) {
+		t.Errorf("want CMD_BUFFER_GTPU error for oid(1, 1), got %v", err)
+	}
+	if len(left) != 2 || left[0].SeqNumber != 1 || left[1].SeqNumber != 2 {
+		t.Errorf("want both packets left in order, got %+v", left)
+	}
+	if pkts[0].SeqNumber != 2 {
+		t.Error("pkts modified")
+	}
+}
@@ -1,26 +1,397 @@
 package gtp5gnl
 
 import (
+	"context"
+	"errors"
+	"sync"
+	"syscall"
+
 	"github.com/khirono/go-genl"
 	"github.com/khirono/go-nl"
 )
 
+// ErrClosed is returned by requests made on a Client after Close.
+var ErrClosed = errors.New("gtp5gnl: client closed")
+
 type Client struct {
 	Client *nl.Client
-	ID     int
+	// ID is the gtp5g family ID. A Client changes it when the family is
+	// resolved again; it must not be read while requests are in flight.
+	ID int
+
+	conn nl.Conner
+	mux  *nl.Mux
+	// sem serializes requests: the mux delivers replies to the most
+	// recently pushed handler first, so only one request may be pending.
+	sem chan struct{}
+	// mu guards ID, groups, ops and caps, which are replaced under sem
+	// when the family is resolved again but read without it.
+	mu     sync.RWMutex
+	caps   Capabilities
+	groups []genl.MulticastGroup
+	ops    []genl.Op
+
+	// Set for clients created by Dial, which own conn and mux.
+	dialed bool
+	broken bool
+	wg     sync.WaitGroup
+	done   chan struct{}
+	once   sync.Once
 }
 
 func NewClient(conn *nl.Conn, mux *nl.Mux) (*Client, error) {
 	c := new(Client)
 	c.Client = nl.NewClient(conn, mux)
+	c.conn = conn
+	c.mux = mux
+	c.sem = make(chan struct{}, 1)
+	c.done = make(chan struct{})
 	f, err := genl.GetFamily(c.Client, "gtp5g")
+	if err != nil {
+		return nil, familyError(err)
+	}
+	c.setFamily(f)
+	c.probe()
+	return c, nil
+}
+
+// Dial opens a generic netlink socket, starts a mux serving it and resolves
+// the gtp5g family. The returned Client owns all of them and must be closed
+// with Close.
+//
+// A Dial client replaces its socket when sending on it fails or when the
+// mux reports a read error on it, and resolves the family again when the
+// kernel answers ENOENT, e.g. after the gtp5g module was reloaded. The
+// request is then sent again only if the family ID changed; if the family
+// is gone, the error matches ErrFamilyNotFound instead of ErrRuleNotFound.
+func Dial() (*Client, error) {
+	mux, err := nl.NewMux()
 	if err != nil {
 		return nil, err
 	}
-	c.ID = int(f.ID)
+	c := new(Client)
+	c.mux = mux
+	c.sem = make(chan struct{}, 1)
+	c.done = make(chan struct{})
+	c.dialed = true
+	c.wg.Add(1)
+	go func() {
+		mux.Serve()
+		c.wg.Done()
+	}()
+
+	conn, err := nl.Open(syscall.NETLINK_GENERIC)
+	if err != nil {
+		c.stopMux()
+		return nil, err
+	}
+	c.setConn(conn)
+	f, err := genl.GetFamily(c.Client, "gtp5g")
+	if err != nil {
+		conn.Close()
+		c.stopMux()
+		return nil, familyError(err)
+	}
+	c.setFamily(f)
+	c.probe()
 	return c, nil
 }
 
+// Close aborts pending requests and, for a Client created by Dial, closes
+// its socket and stops its mux. Clients created by NewClient leave conn and
+// mux to the caller.
+func (c *Client) Close() error {
+	c.once.Do(func() {
+		close(c.done)
+		c.sem <- struct{}{}
+		if c.dialed {
+			c.conn.Close()
+			c.stopMux()
+		}
+	})
+	return nil
+}
+
+func (c *Client) stopMux() {
+	c.mux.Close()
+	c.wg.Wait()
+}
+
+func (c *Client) setConn(conn *nl.Conn) {
+	w := &watchedConn{Conner: conn, failed: make(chan error, 1)}
+	c.conn = w
+	c.Client = nl.NewClient(w, c.mux)
+	c.broken = false
+}
+
+// redial replaces the socket of a Dial client.
+func (c *Client) redial() error {
+	conn, err := nl.Open(syscall.NETLINK_GENERIC)
+	if err != nil {
+		return err
+	}
+	c.conn.Close()
+	c.setConn(conn)
+	return nil
+}
+
+// familyGone is called when a request sent to family id failed with ENOENT,
+// which both the gtp5g module (no such rule) and the generic netlink core
+// (no such family) return. It resolves the family again and reports whether
+// id is stale, probing the capabilities of the new module if so. If the
+// family still has id, the module answered the request itself. If it is not
+// registered at all, the module is not loaded and ErrFamilyNotFound is
+// returned.
+func (c *Client) familyGone(id int) (bool, error) {
+	if id != c.familyID() {
+		// resolved again since req was built
+		return true, nil
+	}
+	f, err := genl.GetFamily(c.Client, "gtp5g")
+	if err != nil {
+		if errors.Is(err, syscall.ENOENT) {
+			c.setCaps(Capabilities{})
+			return false, ErrFamilyNotFound
+		}
+		return false, nil
+	}
+	if int(f.ID) == id {
+		return false, nil
+	}
+	c.setFamily(f)
+	c.probe()
+	return true, nil
+}
+
+func (c *Client) setFamily(f *genl.Family) {
+	c.mu.Lock()
+	c.ID = int(f.ID)
+	c.groups = f.Groups
+	c.ops = f.Ops
+	c.mu.Unlock()
+}
+
+// familyID returns the gtp5g family ID to address requests to.
+func (c *Client) familyID() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.ID
+}
+
+// family returns the gtp5g family ID and its multicast groups.
+func (c *Client) family() (int, []genl.MulticastGroup) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.ID, c.groups
+}
+
 func (c *Client) Do(req *nl.Request) ([]nl.Msg, error) {
-	return c.Client.Do(req)
+	return c.DoContext(context.Background(), req)
+}
+
+// DoContext sends req and waits for its replies until the kernel acks the
+// request or ends the dump, or until ctx is done. In the latter case the
+// returned error names the gtp5g command and wraps ctx.Err().
+func (c *Client) DoContext(ctx context.Context, req *nl.Request) ([]nl.Msg, error) {
+	err := ctx.Err()
+	if err != nil {
+		return nil, requestError(req, err)
+	}
+	select {
+	case c.sem <- struct{}{}:
+	case <-ctx.Done():
+		return nil, requestError(req, ctx.Err())
+	case <-c.done:
+		return nil, ErrClosed
+	}
+	defer func() { <-c.sem }()
+	select {
+	case <-c.done:
+		return nil, ErrClosed
+	default:
+	}
+
+	if c.dialed && (c.broken || c.conn.(*watchedConn).hasFailed()) {
+		err := c.redial()
+		if err != nil {
+			return nil, requestError(req, err)
+		}
+	}
+	rsps, err := c.do(ctx, req)
+	if !errors.Is(err, syscall.ENOENT) {
+		return rsps, err
+	}
+	gone, ferr := c.familyGone(int(req.Header.Type))
+	if ferr != nil {
+		var e *Error
+		if errors.As(err, &e) {
+			e.Err = ferr
+		}
+		return rsps, err
+	}
+	if gone {
+		id := c.familyID()
+		req.Header.Type = uint16(id)
+		req.AppendReplyType(id)
+		rsps, err = c.do(ctx, req)
+	}
+	return rsps, err
+}
+
+func (c *Client) do(ctx context.Context, req *nl.Request) ([]nl.Msg, error) {
+	h := &reqHandler{
+		req:   req,
+		ch:    make(chan *nl.Msg, 32),
+		abort: make(chan struct{}),
+	}
+	defer close(h.abort)
+
+	seq := c.conn.TakeSeq()
+	req.Commit(seq)
+	err := c.mux.PushHandler(c.conn, h)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { c.mux.PopHandler(c.conn) }()
+	_, err = c.conn.Writev(req.Iovs)
+	if err != nil && c.dialed {
+		// Nothing was sent; retry once on a fresh socket.
+		c.mux.PopHandler(c.conn)
+		err = c.redial()
+		if err != nil {
+			return nil, requestError(req, err)
+		}
+		req.Commit(c.conn.TakeSeq())
+		err = c.mux.PushHandler(c.conn, h)
+		if err != nil {
+			return nil, err
+		}
+		_, err = c.conn.Writev(req.Iovs)
+	}
+	if err != nil {
+		return nil, requestError(req, err)
+	}
+
+	var failed chan error
+	if w, ok := c.conn.(*watchedConn); ok {
+		failed = w.failed
+	}
+	var rsps []nl.Msg
+	for {
+		select {
+		case msg, ok := <-h.ch:
+			if !ok {
+				return rsps, nil
+			}
+			switch msg.Header.Type {
+			case syscall.NLMSG_DONE, syscall.NLMSG_ERROR:
+				if len(msg.Body) < 4 {
+					return rsps, nil
+				}
+				err, _, _ := nl.DecodeMsgError(msg.Body)
+				if err != nil {
+					return rsps, requestError(req, err)
+				}
+				return rsps, nil
+			default:
+				rsps = append(rsps, *msg)
+			}
+		case err := <-failed:
+			// Replies may have been lost; the next request uses a
+			// fresh socket.
+			c.broken = true
+			return rsps, requestError(req, err)
+		case <-ctx.Done():
+			return rsps, requestError(req, ctx.Err())
+		case <-c.done:
+			return rsps, ErrClosed
+		}
+	}
+}
+
+// requestError wraps err, an errno from the kernel or a local failure, in
+// an *Error naming the command of req.
+func requestError(req *nl.Request, err error) error {
+	cmd, ok := requestCmd(req)
+	if !ok {
+		return err
+	}
+	return newError(cmd, err)
+}
+
+// requestCmd returns the gtp5g command of req, read from the genl header
+// that is always appended first.
+func requestCmd(req *nl.Request) (int, bool) {
+	if len(req.Iovs) < 2 || req.Iovs[1].Len < 1 {
+		return 0, false
+	}
+	return int(*req.Iovs[1].Base), true
+}
+
+// watchedConn reports the read errors seen by the mux, which otherwise
+// drops them silently.
+type watchedConn struct {
+	nl.Conner
+	failed chan error
+}
+
+// hasFailed reports whether a read error was seen since the last request.
+func (w *watchedConn) hasFailed() bool {
+	select {
+	case <-w.failed:
+		return true
+	default:
+		return false
+	}
+}
+
+func (w *watchedConn) Read(b []byte) (int, error) {
+	n, err := w.Conner.Read(b)
+	if err != nil && err != syscall.EINTR && err != syscall.EAGAIN {
+		select {
+		case w.failed <- err:
+		default:
+		}
+	}
+	return n, err
+}
+
+// reqHandler receives the replies of one request from the mux.
+type reqHandler struct {
+	req   *nl.Request
+	ch    chan *nl.Msg
+	abort chan struct{}
+	done  bool
+}
+
+func (h *reqHandler) ServeMsg(msg *nl.Msg) bool {
+	if h.done {
+		return false
+	}
+	t := msg.Header.Type
+	switch {
+	case t == syscall.NLMSG_DONE:
+	case t == syscall.NLMSG_ERROR:
+	case h.req.ContainsReplyType(int(t)):
+	default:
+		return false
+	}
+	if msg.Header.Seq != h.req.Header.Seq {
+		return false
+	}
+	if msg.Header.Pid == 0 {
+		return false
+	}
+	select {
+	case h.ch <- msg:
+	case <-h.abort:
+		// the caller gave up; drop the rest of the replies
+		h.done = true
+		return true
+	}
+	if t == syscall.NLMSG_DONE || t == syscall.NLMSG_ERROR || !h.req.NeedAck() {
+		h.done = true
+		close(h.ch)
+	}
+	return true
 }
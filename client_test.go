@@ -0,0 +1,102 @@
+package gtp5gnl
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDoContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	c := &Client{sem: make(chan struct{}, 1)}
+	link := &Link{Name: "upfgtp", Index: 1}
+	err := CreatePDROIDContext(ctx, c, link, OID{1, 1}, nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("want context.Canceled, got %v", err)
+	}
+	if !strings.HasPrefix(err.Error(), "CMD_ADD_PDR: ") {
+		t.Errorf("error does not name the command: %v", err)
+	}
+}
+
+func TestCmdString(t *testing.T) {
+	tests := []struct {
+		cmd  int
+		want string
+	}{
+		{CMD_ADD_PDR, "CMD_ADD_PDR"},
+		{CMD_GET_USAGE_STATISTIC, "CMD_GET_USAGE_STATISTIC"},
+		{100, "CMD(100)"},
+	}
+	for _, tt := range tests {
+		got := CmdString(tt.cmd)
+		if got != tt.want {
+			t.Errorf("CmdString(%v) = %q, want %q", tt.cmd, got, tt.want)
+		}
+	}
+}
+
+func TestClientClosed(t *testing.T) {
+	c := &Client{sem: make(chan struct{}, 1), done: make(chan struct{})}
+	c.Close()
+	c.Close()
+
+	link := &Link{Name: "upfgtp", Index: 1}
+	err := CreatePDROID(c, link, OID{1, 1}, nil)
+	if !errors.Is(err, ErrClosed) {
+		t.Fatalf("want ErrClosed, got %v", err)
+	}
+}
+
+func TestDial(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping testing in short mode")
+	}
+
+	c, err := Dial()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	_, err = GetVersion(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the next request after a socket error goes out on a fresh socket
+	c.broken = true
+	_, err = GetVersion(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDialRuleNotFound(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping testing in short mode")
+	}
+
+	c, err := Dial()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	link, err := GetLink("upfgtp")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id := c.ID
+	err = RemovePDROID(c, link, OID{0xffff, 0xffff})
+	if !errors.Is(err, ErrRuleNotFound) || errors.Is(err, ErrFamilyNotFound) {
+		t.Errorf("want ErrRuleNotFound; but got %v\n", err)
+	}
+	if c.ID != id {
+		t.Errorf("want family %v; but got %v\n", id, c.ID)
+	}
+}
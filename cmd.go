@@ -1,5 +1,9 @@
 package gtp5gnl
 
+import (
+	"fmt"
+)
+
 const (
 	CMD_UNSPEC = iota
 
@@ -29,3 +33,35 @@ const (
 	CMD_GET_MULTI_REPORTS
 	CMD_GET_USAGE_STATISTIC
 )
+
+var cmdNames = [...]string{
+	CMD_UNSPEC:              "CMD_UNSPEC",
+	CMD_ADD_PDR:             "CMD_ADD_PDR",
+	CMD_ADD_FAR:             "CMD_ADD_FAR",
+	CMD_ADD_QER:             "CMD_ADD_QER",
+	CMD_DEL_PDR:             "CMD_DEL_PDR",
+	CMD_DEL_FAR:             "CMD_DEL_FAR",
+	CMD_DEL_QER:             "CMD_DEL_QER",
+	CMD_GET_PDR:             "CMD_GET_PDR",
+	CMD_GET_FAR:             "CMD_GET_FAR",
+	CMD_GET_QER:             "CMD_GET_QER",
+	CMD_ADD_URR:             "CMD_ADD_URR",
+	CMD_ADD_BAR:             "CMD_ADD_BAR",
+	CMD_DEL_URR:             "CMD_DEL_URR",
+	CMD_DEL_BAR:             "CMD_DEL_BAR",
+	CMD_GET_URR:             "CMD_GET_URR",
+	CMD_GET_BAR:             "CMD_GET_BAR",
+	CMD_GET_VERSION:         "CMD_GET_VERSION",
+	CMD_GET_REPORT:          "CMD_GET_REPORT",
+	CMD_BUFFER_GTPU:         "CMD_BUFFER_GTPU",
+	CMD_GET_MULTI_REPORTS:   "CMD_GET_MULTI_REPORTS",
+	CMD_GET_USAGE_STATISTIC: "CMD_GET_USAGE_STATISTIC",
+}
+
+// CmdString returns the name of a gtp5g command, e.g. "CMD_ADD_PDR".
+func CmdString(cmd int) string {
+	if cmd >= 0 && cmd < len(cmdNames) {
+		return cmdNames[cmd]
+	}
+	return fmt.Sprintf("CMD(%d)", cmd)
+}
@@ -0,0 +1,173 @@
+package gtp5gnl
+
+import (
+	"errors"
+	"fmt"
+	"syscall"
+)
+
+var (
+	// ErrRuleExists is returned when creating a rule whose ID is taken
+	// (EEXIST).
+	ErrRuleExists = errors.New("rule already exists")
+	// ErrRuleNotFound is returned when the rule of a request does not
+	// exist (ENOENT), or the kernel returned no rule for it.
+	ErrRuleNotFound = errors.New("rule not found")
+	// ErrInvalidAttr is returned when the kernel rejects the attributes of
+	// a request (EINVAL, ERANGE).
+	ErrInvalidAttr = errors.New("invalid attribute")
+	// ErrFamilyNotFound is returned when the gtp5g generic netlink family
+	// is not registered, i.e. the gtp5g module is not loaded.
+	ErrFamilyNotFound = errors.New("gtp5g family not found")
+)
+
+// Error describes a failed gtp5g request. It matches both its sentinel
+// (ErrRuleExists, ...) and its errno with errors.Is:
+//
+//	var e *gtp5gnl.Error
+//	if errors.As(err, &e) && errors.Is(e, gtp5gnl.ErrRuleExists) {
+//		log.Printf("%v %v exists", e.Kind, e.OID)
+//	}
+type Error struct {
+	Cmd   int           // gtp5g command, e.g. CMD_ADD_PDR
+	Kind  string        // "PDR", "FAR", "QER", "URR", "BAR", "BUFFER" or ""
+	OID   OID           // nil if the request has no single rule
+	Errno syscall.Errno // 0 if the kernel did not fail the request
+	Err   error         // sentinel or cause; nil if only Errno is known
+}
+
+func (e *Error) Error() string {
+	s := CmdString(e.Cmd) + ": "
+	if e.OID != nil {
+		s += fmt.Sprintf("%v oid(%v): ", e.Kind, e.OID)
+	}
+	switch {
+	case e.Err != nil && e.Errno != 0:
+		return s + fmt.Sprintf("%v (%v)", e.Err, e.Errno)
+	case e.Err != nil:
+		return s + e.Err.Error()
+	default:
+		return s + e.Errno.Error()
+	}
+}
+
+func (e *Error) Unwrap() []error {
+	var errs []error
+	if e.Err != nil {
+		errs = append(errs, e.Err)
+	}
+	if e.Errno != 0 {
+		errs = append(errs, e.Errno)
+	}
+	return errs
+}
+
+func errnoSentinel(errno syscall.Errno) error {
+	switch errno {
+	case syscall.EEXIST:
+		return ErrRuleExists
+	case syscall.ENOENT:
+		return ErrRuleNotFound
+	case syscall.EINVAL, syscall.ERANGE:
+		return ErrInvalidAttr
+	default:
+		return nil
+	}
+}
+
+var cmdKinds = map[int]string{
+	CMD_ADD_PDR: "PDR",
+	CMD_DEL_PDR: "PDR",
+	CMD_GET_PDR: "PDR",
+	CMD_ADD_FAR: "FAR",
+	CMD_DEL_FAR: "FAR",
+	CMD_GET_FAR: "FAR",
+	CMD_ADD_QER: "QER",
+	CMD_DEL_QER: "QER",
+	CMD_GET_QER: "QER",
+	CMD_ADD_URR: "URR",
+	CMD_DEL_URR: "URR",
+	CMD_GET_URR: "URR",
+	CMD_ADD_BAR: "BAR",
+	CMD_DEL_BAR: "BAR",
+	CMD_GET_BAR: "BAR",
+
+	CMD_GET_REPORT:        "URR",
+	CMD_GET_MULTI_REPORTS: "URR",
+	CMD_BUFFER_GTPU:       "BUFFER",
+}
+
+func newError(cmd int, err error) *Error {
+	e := &Error{Cmd: cmd, Kind: cmdKinds[cmd]}
+	var errno syscall.Errno
+	if errors.As(err, &errno) {
+		e.Errno = errno
+		e.Err = errnoSentinel(errno)
+	} else {
+		e.Err = err
+	}
+	return e
+}
+
+// withOID records oid in err if it is an *Error.
+func withOID(err error, oid OID) error {
+	var e *Error
+	if errors.As(err, &e) && e.OID == nil {
+		e.OID = oid
+	}
+	return err
+}
+
+// notFound is returned when a request for the rule of oid succeeded
+// without a reply.
+func notFound(cmd int, oid OID) error {
+	return &Error{Cmd: cmd, Kind: cmdKinds[cmd], OID: oid, Err: ErrRuleNotFound}
+}
+
+func familyError(err error) error {
+	if errors.Is(err, syscall.ENOENT) {
+		return fmt.Errorf("%w: %w", ErrFamilyNotFound, err)
+	}
+	return err
+}
+
+// UnsupportedError is returned before sending a request that uses a feature
+// the loaded gtp5g module does not support. It matches
+// errors.ErrUnsupported.
+type UnsupportedError struct {
+	Feature string
+	Version Version
+}
+
+func (e *UnsupportedError) Error() string {
+	return fmt.Sprintf("%v unsupported by gtp5g v%d.%d", e.Feature, e.Version.Major, e.Version.Minor)
+}
+
+func (e *UnsupportedError) Unwrap() error {
+	return errors.ErrUnsupported
+}
+
+// DecodeError is returned by the Decode functions for an attribute that is
+// truncated or malformed. Path names the attribute from the outermost one
+// decoded, e.g. "PDR.PDI.F_TEID.I_TEID".
+type DecodeError struct {
+	Path string
+	Len  int   // bytes received
+	Want int   // bytes expected
+	Err  error // cause if the length is fine but the value is not
+}
+
+func (e *DecodeError) Error() string {
+	s := "decode"
+	if e.Path != "" {
+		s += " " + e.Path
+	}
+	if e.Err != nil {
+		return fmt.Sprintf("%v: %v", s, e.Err)
+	}
+	return fmt.Sprintf("%v: %d bytes, want %d", s, e.Len, e.Want)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,90 @@
+package gtp5gnl
+
+import (
+	"context"
+	"errors"
+	"syscall"
+	"testing"
+)
+
+func TestErrorIs(t *testing.T) {
+	tests := []struct {
+		errno syscall.Errno
+		want  error
+	}{
+		{syscall.EEXIST, ErrRuleExists},
+		{syscall.ENOENT, ErrRuleNotFound},
+		{syscall.EINVAL, ErrInvalidAttr},
+		{syscall.ERANGE, ErrInvalidAttr},
+	}
+	for _, tt := range tests {
+		err := withOID(newError(CMD_ADD_FAR, tt.errno), OID{1, 2})
+		if !errors.Is(err, tt.want) {
+			t.Errorf("%v: want %v", err, tt.want)
+		}
+		if !errors.Is(err, tt.errno) {
+			t.Errorf("%v: want %v", err, tt.errno)
+		}
+		var e *Error
+		if !errors.As(err, &e) {
+			t.Fatalf("%v: not an *Error", err)
+		}
+		if e.Cmd != CMD_ADD_FAR || e.Kind != "FAR" || !e.OID.Equal(OID{1, 2}) {
+			t.Errorf("unexpected error fields: %+v", e)
+		}
+	}
+
+	err := newError(CMD_GET_PDR, syscall.ENOMEM)
+	if errors.Is(err, ErrRuleNotFound) || errors.Is(err, ErrInvalidAttr) {
+		t.Errorf("%v: matches a sentinel", err)
+	}
+	if !errors.Is(err, syscall.ENOMEM) {
+		t.Errorf("%v: want ENOMEM", err)
+	}
+
+	err = newError(CMD_DEL_URR, context.DeadlineExceeded)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("%v: want context.DeadlineExceeded", err)
+	}
+}
+
+func TestErrorString(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{
+			withOID(newError(CMD_ADD_PDR, syscall.EEXIST), OID{1, 3}),
+			"CMD_ADD_PDR: PDR oid([1 3]): rule already exists (file exists)",
+		},
+		{
+			notFound(CMD_GET_QER, OID{2}),
+			"CMD_GET_QER: QER oid([2]): rule not found",
+		},
+		{
+			withOID(newError(CMD_BUFFER_GTPU, syscall.ENOENT), OID{1, 2}),
+			"CMD_BUFFER_GTPU: BUFFER oid([1 2]): rule not found (no such file or directory)",
+		},
+		{
+			newError(CMD_GET_VERSION, syscall.ENOMEM),
+			"CMD_GET_VERSION: cannot allocate memory",
+		},
+	}
+	for _, tt := range tests {
+		got := tt.err.Error()
+		if got != tt.want {
+			t.Errorf("got %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestFamilyError(t *testing.T) {
+	err := familyError(syscall.ENOENT)
+	if !errors.Is(err, ErrFamilyNotFound) || !errors.Is(err, syscall.ENOENT) {
+		t.Errorf("%v: want ErrFamilyNotFound and ENOENT", err)
+	}
+	err = familyError(syscall.EPERM)
+	if errors.Is(err, ErrFamilyNotFound) {
+		t.Errorf("%v: want no ErrFamilyNotFound", err)
+	}
+}
@@ -1,6 +1,7 @@
 package gtp5gnl
 
 import (
+	"context"
 	"fmt"
 	"syscall"
 
@@ -25,9 +26,13 @@ func CreateFAR(c *Client, link *Link, farid int, attrs []nl.Attr) error {
 }
 
 func CreateFAROID(c *Client, link *Link, oid OID, attrs []nl.Attr) error {
+	return CreateFAROIDContext(context.Background(), c, link, oid, attrs)
+}
+
+func CreateFAROIDContext(ctx context.Context, c *Client, link *Link, oid OID, attrs []nl.Attr) error {
 	flags := syscall.NLM_F_EXCL
 	flags |= syscall.NLM_F_ACK
-	req := nl.NewRequest(c.ID, flags)
+	req := nl.NewRequest(c.familyID(), flags)
 	err := req.Append(genl.Header{Cmd: CMD_ADD_FAR})
 	if err != nil {
 		return err
@@ -63,8 +68,8 @@ func CreateFAROID(c *Client, link *Link, oid OID, attrs []nl.Attr) error {
 	if err != nil {
 		return err
 	}
-	_, err = c.Do(req)
-	return err
+	_, err = c.DoContext(ctx, req)
+	return withOID(err, oid)
 }
 
 func UpdateFAR(c *Client, link *Link, farid int, attrs []nl.Attr) error {
@@ -72,9 +77,13 @@ func UpdateFAR(c *Client, link *Link, farid int, attrs []nl.Attr) error {
 }
 
 func UpdateFAROID(c *Client, link *Link, oid OID, attrs []nl.Attr) error {
+	return UpdateFAROIDContext(context.Background(), c, link, oid, attrs)
+}
+
+func UpdateFAROIDContext(ctx context.Context, c *Client, link *Link, oid OID, attrs []nl.Attr) error {
 	flags := syscall.NLM_F_REPLACE
 	flags |= syscall.NLM_F_ACK
-	req := nl.NewRequest(c.ID, flags)
+	req := nl.NewRequest(c.familyID(), flags)
 	err := req.Append(genl.Header{Cmd: CMD_ADD_FAR})
 	if err != nil {
 		return err
@@ -110,8 +119,8 @@ func UpdateFAROID(c *Client, link *Link, oid OID, attrs []nl.Attr) error {
 	if err != nil {
 		return err
 	}
-	_, err = c.Do(req)
-	return err
+	_, err = c.DoContext(ctx, req)
+	return withOID(err, oid)
 }
 
 func RemoveFAR(c *Client, link *Link, farid int) error {
@@ -119,9 +128,13 @@ func RemoveFAR(c *Client, link *Link, farid int) error {
 }
 
 func RemoveFAROID(c *Client, link *Link, oid OID) error {
+	return RemoveFAROIDContext(context.Background(), c, link, oid)
+}
+
+func RemoveFAROIDContext(ctx context.Context, c *Client, link *Link, oid OID) error {
 	flags := syscall.NLM_F_EXCL
 	flags |= syscall.NLM_F_ACK
-	req := nl.NewRequest(c.ID, flags)
+	req := nl.NewRequest(c.familyID(), flags)
 	err := req.Append(genl.Header{Cmd: CMD_DEL_FAR})
 	if err != nil {
 		return err
@@ -153,8 +166,8 @@ func RemoveFAROID(c *Client, link *Link, oid OID) error {
 			return err
 		}
 	}
-	_, err = c.Do(req)
-	return err
+	_, err = c.DoContext(ctx, req)
+	return withOID(err, oid)
 }
 
 func GetFAR(c *Client, link *Link, farid int) (*FAR, error) {
@@ -162,8 +175,12 @@ func GetFAR(c *Client, link *Link, farid int) (*FAR, error) {
 }
 
 func GetFAROID(c *Client, link *Link, oid OID) (*FAR, error) {
+	return GetFAROIDContext(context.Background(), c, link, oid)
+}
+
+func GetFAROIDContext(ctx context.Context, c *Client, link *Link, oid OID) (*FAR, error) {
 	flags := syscall.NLM_F_ACK
-	req := nl.NewRequest(c.ID, flags)
+	req := nl.NewRequest(c.familyID(), flags)
 	err := req.Append(genl.Header{Cmd: CMD_GET_FAR})
 	if err != nil {
 		return nil, err
@@ -195,12 +212,12 @@ func GetFAROID(c *Client, link *Link, oid OID) (*FAR, error) {
 			return nil, err
 		}
 	}
-	rsps, err := c.Do(req)
+	rsps, err := c.DoContext(ctx, req)
 	if err != nil {
-		return nil, err
+		return nil, withOID(err, oid)
 	}
 	if len(rsps) < 1 {
-		return nil, fmt.Errorf("nil FAR of oid(%v)", oid)
+		return nil, notFound(CMD_GET_FAR, oid)
 	}
 	far, err := DecodeFAR(rsps[0].Body[genl.SizeofHeader:])
 	if err != nil {
@@ -210,13 +227,17 @@ func GetFAROID(c *Client, link *Link, oid OID) (*FAR, error) {
 }
 
 func GetFARAll(c *Client) ([]FAR, error) {
+	return GetFARAllContext(context.Background(), c)
+}
+
+func GetFARAllContext(ctx context.Context, c *Client) ([]FAR, error) {
 	flags := syscall.NLM_F_DUMP
-	req := nl.NewRequest(c.ID, flags)
+	req := nl.NewRequest(c.familyID(), flags)
 	err := req.Append(genl.Header{Cmd: CMD_GET_FAR})
 	if err != nil {
 		return nil, err
 	}
-	rsps, err := c.Do(req)
+	rsps, err := c.DoContext(ctx, req)
 	if err != nil {
 		return nil, err
 	}
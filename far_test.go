@@ -3,8 +3,6 @@ package gtp5gnl
 import (
 	"encoding/json"
 	"log"
-	"sync"
-	"syscall"
 	"testing"
 
 	"github.com/khirono/go-nl"
@@ -15,31 +13,11 @@ func TestCreateFAR(t *testing.T) {
 		t.Skip("skipping testing in short mode")
 	}
 
-	var wg sync.WaitGroup
-	mux, err := nl.NewMux()
-	if err != nil {
-		t.Fatal(err)
-	}
-	defer func() {
-		mux.Close()
-		wg.Wait()
-	}()
-	wg.Add(1)
-	go func() {
-		mux.Serve()
-		wg.Done()
-	}()
-
-	conn, err := nl.Open(syscall.NETLINK_GENERIC)
-	if err != nil {
-		t.Fatal(err)
-	}
-	defer conn.Close()
-
-	c, err := NewClient(conn, mux)
+	c, err := Dial()
 	if err != nil {
 		t.Fatal(err)
 	}
+	defer c.Close()
 
 	link, err := GetLink("upfgtp")
 	if err != nil {
@@ -94,31 +72,11 @@ func TestGetFAR(t *testing.T) {
 		t.Skip("skipping testing in short mode")
 	}
 
-	var wg sync.WaitGroup
-	mux, err := nl.NewMux()
-	if err != nil {
-		t.Fatal(err)
-	}
-	defer func() {
-		mux.Close()
-		wg.Wait()
-	}()
-	wg.Add(1)
-	go func() {
-		mux.Serve()
-		wg.Done()
-	}()
-
-	conn, err := nl.Open(syscall.NETLINK_GENERIC)
-	if err != nil {
-		t.Fatal(err)
-	}
-	defer conn.Close()
-
-	c, err := NewClient(conn, mux)
+	c, err := Dial()
 	if err != nil {
 		t.Fatal(err)
 	}
+	defer c.Close()
 
 	link, err := GetLink("upfgtp")
 	if err != nil {
@@ -142,31 +100,11 @@ func TestGetFARAll(t *testing.T) {
 		t.Skip("skipping testing in short mode")
 	}
 
-	var wg sync.WaitGroup
-	mux, err := nl.NewMux()
-	if err != nil {
-		t.Fatal(err)
-	}
-	defer func() {
-		mux.Close()
-		wg.Wait()
-	}()
-	wg.Add(1)
-	go func() {
-		mux.Serve()
-		wg.Done()
-	}()
-
-	conn, err := nl.Open(syscall.NETLINK_GENERIC)
-	if err != nil {
-		t.Fatal(err)
-	}
-	defer conn.Close()
-
-	c, err := NewClient(conn, mux)
+	c, err := Dial()
 	if err != nil {
 		t.Fatal(err)
 	}
+	defer c.Close()
 
 	fars, err := GetFARAll(c)
 	if err != nil {
@@ -185,31 +123,11 @@ func TestRemoveFAR(t *testing.T) {
 		t.Skip("skipping testing in short mode")
 	}
 
-	var wg sync.WaitGroup
-	mux, err := nl.NewMux()
-	if err != nil {
-		t.Fatal(err)
-	}
-	defer func() {
-		mux.Close()
-		wg.Wait()
-	}()
-	wg.Add(1)
-	go func() {
-		mux.Serve()
-		wg.Done()
-	}()
-
-	conn, err := nl.Open(syscall.NETLINK_GENERIC)
-	if err != nil {
-		t.Fatal(err)
-	}
-	defer conn.Close()
-
-	c, err := NewClient(conn, mux)
+	c, err := Dial()
 	if err != nil {
 		t.Fatal(err)
 	}
+	defer c.Close()
 
 	link, err := GetLink("upfgtp")
 	if err != nil {
@@ -0,0 +1,95 @@
+package gtp5gnl
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"unsafe"
+)
+
+// flagBits is the underlying type of a bitmask IE.
+type flagBits interface {
+	~uint8 | ~uint16 | ~uint32
+}
+
+type flagName[T flagBits] struct {
+	flag T
+	name string
+}
+
+// flagList returns the names of the flags set in f; bits without a name
+// are returned as one hex number.
+func flagList[T flagBits](f T, names []flagName[T]) []string {
+	list := []string{}
+	for _, n := range names {
+		if f&n.flag != 0 {
+			list = append(list, n.name)
+			f &^= n.flag
+		}
+	}
+	if f != 0 {
+		list = append(list, fmt.Sprintf("0x%x", uint64(f)))
+	}
+	return list
+}
+
+// formatFlags joins the names of the flags set in f by "|", e.g.
+// "DROBU|SNDEM", or returns "0" if none is set.
+func formatFlags[T flagBits](f T, names []flagName[T]) string {
+	list := flagList(f, names)
+	if len(list) == 0 {
+		return "0"
+	}
+	return strings.Join(list, "|")
+}
+
+// parseFlags parses a number or flag names separated by "|" or ",". Names
+// are case-insensitive.
+func parseFlags[T flagBits](s string, names []flagName[T], what string) (T, error) {
+	var f T
+	v, err := strconv.ParseUint(s, 0, int(unsafe.Sizeof(f))*8)
+	if err == nil {
+		return T(v), nil
+	}
+	for _, name := range strings.FieldsFunc(s, func(r rune) bool { return r == '|' || r == ',' }) {
+		name = strings.TrimSpace(name)
+		found := false
+		for _, n := range names {
+			if strings.EqualFold(name, n.name) {
+				f |= n.flag
+				found = true
+				break
+			}
+		}
+		if !found {
+			v, err := strconv.ParseUint(name, 0, int(unsafe.Sizeof(f))*8)
+			if err != nil {
+				return 0, fmt.Errorf("unknown %v flag %q", what, name)
+			}
+			f |= T(v)
+		}
+	}
+	return f, nil
+}
+
+// marshalFlags encodes f as a JSON list of flag names.
+func marshalFlags[T flagBits](f T, names []flagName[T]) ([]byte, error) {
+	return json.Marshal(flagList(f, names))
+}
+
+// unmarshalFlags decodes a JSON list of flag names or a number.
+func unmarshalFlags[T flagBits](b []byte, names []flagName[T], what string) (T, error) {
+	var list []string
+	if err := json.Unmarshal(b, &list); err == nil {
+		return parseFlags(strings.Join(list, "|"), names, what)
+	}
+	var v uint64
+	if err := json.Unmarshal(b, &v); err != nil {
+		return 0, err
+	}
+	if uint64(T(v)) != v {
+		return 0, fmt.Errorf("%v flags %v out of range", what, v)
+	}
+	return T(v), nil
+}
@@ -1,5 +1,7 @@
 package gtp5gnl
 
+// Index of the gtp5g multicast groups in the family, as resolved by the
+// controller. Usage reports are pushed to GENL_MCGRP.
 const (
 	GENL_MCGRP = iota
 )
@@ -1,6 +1,7 @@
 package gtp5gnl
 
 import (
+	"context"
 	"fmt"
 	"syscall"
 
@@ -28,9 +29,13 @@ func CreatePDR(c *Client, link *Link, pdrid int, attrs []nl.Attr) error {
 }
 
 func CreatePDROID(c *Client, link *Link, oid OID, attrs []nl.Attr) error {
+	return CreatePDROIDContext(context.Background(), c, link, oid, attrs)
+}
+
+func CreatePDROIDContext(ctx context.Context, c *Client, link *Link, oid OID, attrs []nl.Attr) error {
 	flags := syscall.NLM_F_EXCL
 	flags |= syscall.NLM_F_ACK
-	req := nl.NewRequest(c.ID, flags)
+	req := nl.NewRequest(c.familyID(), flags)
 	err := req.Append(genl.Header{Cmd: CMD_ADD_PDR})
 	if err != nil {
 		return err
@@ -66,8 +71,8 @@ func CreatePDROID(c *Client, link *Link, oid OID, attrs []nl.Attr) error {
 	if err != nil {
 		return err
 	}
-	_, err = c.Do(req)
-	return err
+	_, err = c.DoContext(ctx, req)
+	return withOID(err, oid)
 }
 
 func UpdatePDR(c *Client, link *Link, pdrid int, attrs []nl.Attr) error {
@@ -75,9 +80,13 @@ func UpdatePDR(c *Client, link *Link, pdrid int, attrs []nl.Attr) error {
 }
 
 func UpdatePDROID(c *Client, link *Link, oid OID, attrs []nl.Attr) error {
+	return UpdatePDROIDContext(context.Background(), c, link, oid, attrs)
+}
+
+func UpdatePDROIDContext(ctx context.Context, c *Client, link *Link, oid OID, attrs []nl.Attr) error {
 	flags := syscall.NLM_F_REPLACE
 	flags |= syscall.NLM_F_ACK
-	req := nl.NewRequest(c.ID, flags)
+	req := nl.NewRequest(c.familyID(), flags)
 	err := req.Append(genl.Header{Cmd: CMD_ADD_PDR})
 	if err != nil {
 		return err
@@ -113,8 +122,8 @@ func UpdatePDROID(c *Client, link *Link, oid OID, attrs []nl.Attr) error {
 	if err != nil {
 		return err
 	}
-	_, err = c.Do(req)
-	return err
+	_, err = c.DoContext(ctx, req)
+	return withOID(err, oid)
 }
 
 func RemovePDR(c *Client, link *Link, pdrid int) error {
@@ -122,9 +131,13 @@ func RemovePDR(c *Client, link *Link, pdrid int) error {
 }
 
 func RemovePDROID(c *Client, link *Link, oid OID) error {
+	return RemovePDROIDContext(context.Background(), c, link, oid)
+}
+
+func RemovePDROIDContext(ctx context.Context, c *Client, link *Link, oid OID) error {
 	flags := syscall.NLM_F_EXCL
 	flags |= syscall.NLM_F_ACK
-	req := nl.NewRequest(c.ID, flags)
+	req := nl.NewRequest(c.familyID(), flags)
 	err := req.Append(genl.Header{Cmd: CMD_DEL_PDR})
 	if err != nil {
 		return err
@@ -156,8 +169,8 @@ func RemovePDROID(c *Client, link *Link, oid OID) error {
 			return err
 		}
 	}
-	_, err = c.Do(req)
-	return err
+	_, err = c.DoContext(ctx, req)
+	return withOID(err, oid)
 }
 
 func GetPDR(c *Client, link *Link, pdrid int) (*PDR, error) {
@@ -165,8 +178,12 @@ func GetPDR(c *Client, link *Link, pdrid int) (*PDR, error) {
 }
 
 func GetPDROID(c *Client, link *Link, oid OID) (*PDR, error) {
+	return GetPDROIDContext(context.Background(), c, link, oid)
+}
+
+func GetPDROIDContext(ctx context.Context, c *Client, link *Link, oid OID) (*PDR, error) {
 	flags := syscall.NLM_F_ACK
-	req := nl.NewRequest(c.ID, flags)
+	req := nl.NewRequest(c.familyID(), flags)
 	err := req.Append(genl.Header{Cmd: CMD_GET_PDR})
 	if err != nil {
 		return nil, err
@@ -198,12 +215,12 @@ func GetPDROID(c *Client, link *Link, oid OID) (*PDR, error) {
 			return nil, err
 		}
 	}
-	rsps, err := c.Do(req)
+	rsps, err := c.DoContext(ctx, req)
 	if err != nil {
-		return nil, err
+		return nil, withOID(err, oid)
 	}
 	if len(rsps) < 1 {
-		return nil, fmt.Errorf("nil PDR of oid(%v)", oid)
+		return nil, notFound(CMD_GET_PDR, oid)
 	}
 	pdr, err := DecodePDR(rsps[0].Body[genl.SizeofHeader:])
 	if err != nil {
@@ -213,13 +230,17 @@ func GetPDROID(c *Client, link *Link, oid OID) (*PDR, error) {
 }
 
 func GetPDRAll(c *Client) ([]PDR, error) {
+	return GetPDRAllContext(context.Background(), c)
+}
+
+func GetPDRAllContext(ctx context.Context, c *Client) ([]PDR, error) {
 	flags := syscall.NLM_F_DUMP
-	req := nl.NewRequest(c.ID, flags)
+	req := nl.NewRequest(c.familyID(), flags)
 	err := req.Append(genl.Header{Cmd: CMD_GET_PDR})
 	if err != nil {
 		return nil, err
 	}
-	rsps, err := c.Do(req)
+	rsps, err := c.DoContext(ctx, req)
 	if err != nil {
 		return nil, err
 	}
@@ -3,8 +3,6 @@ package gtp5gnl
 import (
 	"encoding/json"
 	"log"
-	"sync"
-	"syscall"
 	"testing"
 
 	"github.com/khirono/go-nl"
@@ -15,31 +13,11 @@ func TestCreatePDR(t *testing.T) {
 		t.Skip("skipping testing in short mode")
 	}
 
-	var wg sync.WaitGroup
-	mux, err := nl.NewMux()
-	if err != nil {
-		t.Fatal(err)
-	}
-	defer func() {
-		mux.Close()
-		wg.Wait()
-	}()
-	wg.Add(1)
-	go func() {
-		mux.Serve()
-		wg.Done()
-	}()
-
-	conn, err := nl.Open(syscall.NETLINK_GENERIC)
-	if err != nil {
-		t.Fatal(err)
-	}
-	defer conn.Close()
-
-	c, err := NewClient(conn, mux)
+	c, err := Dial()
 	if err != nil {
 		t.Fatal(err)
 	}
+	defer c.Close()
 
 	link, err := GetLink("upfgtp")
 	if err != nil {
@@ -102,31 +80,11 @@ func TestGetPDR(t *testing.T) {
 		t.Skip("skipping testing in short mode")
 	}
 
-	var wg sync.WaitGroup
-	mux, err := nl.NewMux()
-	if err != nil {
-		t.Fatal(err)
-	}
-	defer func() {
-		mux.Close()
-		wg.Wait()
-	}()
-	wg.Add(1)
-	go func() {
-		mux.Serve()
-		wg.Done()
-	}()
-
-	conn, err := nl.Open(syscall.NETLINK_GENERIC)
-	if err != nil {
-		t.Fatal(err)
-	}
-	defer conn.Close()
-
-	c, err := NewClient(conn, mux)
+	c, err := Dial()
 	if err != nil {
 		t.Fatal(err)
 	}
+	defer c.Close()
 
 	link, err := GetLink("upfgtp")
 	if err != nil {
@@ -150,31 +108,11 @@ func TestGetPDRAll(t *testing.T) {
 		t.Skip("skipping testing in short mode")
 	}
 
-	var wg sync.WaitGroup
-	mux, err := nl.NewMux()
-	if err != nil {
-		t.Fatal(err)
-	}
-	defer func() {
-		mux.Close()
-		wg.Wait()
-	}()
-	wg.Add(1)
-	go func() {
-		mux.Serve()
-		wg.Done()
-	}()
-
-	conn, err := nl.Open(syscall.NETLINK_GENERIC)
-	if err != nil {
-		t.Fatal(err)
-	}
-	defer conn.Close()
-
-	c, err := NewClient(conn, mux)
+	c, err := Dial()
 	if err != nil {
 		t.Fatal(err)
 	}
+	defer c.Close()
 
 	pdrs, err := GetPDRAll(c)
 	if err != nil {
@@ -193,31 +131,11 @@ func TestRemovePDR(t *testing.T) {
 		t.Skip("skipping testing in short mode")
 	}
 
-	var wg sync.WaitGroup
-	mux, err := nl.NewMux()
-	if err != nil {
-		t.Fatal(err)
-	}
-	defer func() {
-		mux.Close()
-		wg.Wait()
-	}()
-	wg.Add(1)
-	go func() {
-		mux.Serve()
-		wg.Done()
-	}()
-
-	conn, err := nl.Open(syscall.NETLINK_GENERIC)
-	if err != nil {
-		t.Fatal(err)
-	}
-	defer conn.Close()
-
-	c, err := NewClient(conn, mux)
+	c, err := Dial()
 	if err != nil {
 		t.Fatal(err)
 	}
+	defer c.Close()
 
 	link, err := GetLink("upfgtp")
 	if err != nil {
@@ -0,0 +1,306 @@
+package gtp5gnl
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+// ReportPollerOptions tunes a ReportPoller. The zero value is usable.
+type ReportPollerOptions struct {
+	// OnReport is called with each report from the goroutine running
+	// Run. If nil, the reports are sent on Reports.
+	OnReport func(USAReport)
+	// OnError is called when the reports of a URR could not be read
+	// after the retries, or when the kernel no longer knows the URR; the
+	// URR is unregistered in the latter case. It may be nil.
+	OnError func(oid OID, err error)
+	// Retries is the number of times a failed read is retried before the
+	// URR waits for its next period; default 2, negative for none.
+	Retries int
+	// RetryDelay is the delay before a retry; default 1s.
+	RetryDelay time.Duration
+	// Window is how much earlier than its due time a URR may be read so
+	// that it shares a request with other URRs; default 100ms.
+	Window time.Duration
+	// MultiReports tunes the multi-report requests.
+	MultiReports *MultiReportsOptions
+}
+
+// ReportPoller reads the usage reports of the registered URRs at the end
+// of each of their measurement periods. The URRs due at about the same
+// time are read with one GetMultiReportsOIDResults call.
+//
+// Periods are kept on a fixed grid from the time a URR was added, using
+// the monotonic clock, so late reads do not push later ones back. Periods
+// missed entirely, e.g. while a request was blocked, are skipped rather
+// than read in a burst.
+type ReportPoller struct {
+	c    *Client
+	link *Link
+	opts ReportPollerOptions
+	ch   chan USAReport
+
+	mu   sync.Mutex
+	urrs map[URRKey]*polledURR
+	wake chan struct{}
+
+	// replaced by tests
+	multi  func(ctx context.Context, oids []OID) (map[URRKey]ReportResult, error)
+	single func(ctx context.Context, oid OID) ([]USAReport, error)
+}
+
+type polledURR struct {
+	period  time.Duration
+	due     time.Time // end of the current period
+	retry   time.Time // time of the next retry, if any
+	retries int
+}
+
+// at returns when u is to be read next.
+func (u *polledURR) at() time.Time {
+	if !u.retry.IsZero() {
+		return u.retry
+	}
+	return u.due
+}
+
+// advance moves u to the first period ending after now.
+func (u *polledURR) advance(now time.Time) {
+	u.retry = time.Time{}
+	u.retries = 0
+	u.due = u.due.Add(u.period)
+	if !u.due.After(now) {
+		n := now.Sub(u.due)/u.period + 1
+		u.due = u.due.Add(n * u.period)
+	}
+}
+
+func NewReportPoller(c *Client, link *Link, opts *ReportPollerOptions) *ReportPoller {
+	p := &ReportPoller{
+		c:    c,
+		link: link,
+		ch:   make(chan USAReport),
+		urrs: make(map[URRKey]*polledURR),
+		wake: make(chan struct{}, 1),
+	}
+	if opts != nil {
+		p.opts = *opts
+	}
+	if p.opts.Retries == 0 {
+		p.opts.Retries = 2
+	}
+	if p.opts.RetryDelay <= 0 {
+		p.opts.RetryDelay = time.Second
+	}
+	if p.opts.Window <= 0 {
+		p.opts.Window = 100 * time.Millisecond
+	}
+	p.multi = func(ctx context.Context, oids []OID) (map[URRKey]ReportResult, error) {
+		return GetMultiReportsOIDResultsContext(ctx, c, link, oids, p.opts.MultiReports)
+	}
+	p.single = func(ctx context.Context, oid OID) ([]USAReport, error) {
+		return GetReportOIDContext(ctx, c, link, oid)
+	}
+	return p
+}
+
+// Reports returns the channel the reports are sent on if OnReport is nil.
+func (p *ReportPoller) Reports() <-chan USAReport {
+	return p.ch
+}
+
+// Add registers the URR of oid, replacing a previous registration. Its
+// first period ends period from now.
+func (p *ReportPoller) Add(oid OID, period time.Duration) error {
+	if _, ok := oid.SEID(); !ok {
+		return fmt.Errorf("invalid oid: %v", oid)
+	}
+	if period <= 0 {
+		return fmt.Errorf("invalid period %v for oid(%v)", period, oid)
+	}
+	p.mu.Lock()
+	p.urrs[urrKey(oid)] = &polledURR{period: period, due: time.Now().Add(period)}
+	p.mu.Unlock()
+	p.notify()
+	return nil
+}
+
+// AddURR registers urr of session seid with its measurement period.
+func (p *ReportPoller) AddURR(seid uint64, urr *URR) error {
+	oid := OID{seid, uint64(urr.ID)}
+	if urr.Period == nil {
+		return fmt.Errorf("URR oid(%v) has no measurement period", oid)
+	}
+	return p.Add(oid, time.Duration(*urr.Period)*time.Second)
+}
+
+// Remove unregisters the URR of oid. Reports read for it by a request in
+// flight are discarded.
+func (p *ReportPoller) Remove(oid OID) {
+	p.mu.Lock()
+	delete(p.urrs, urrKey(oid))
+	p.mu.Unlock()
+	p.notify()
+}
+
+// Len returns the number of registered URRs.
+func (p *ReportPoller) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.urrs)
+}
+
+func (p *ReportPoller) notify() {
+	select {
+	case p.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run reads the reports of the registered URRs when they are due until
+// ctx is done, and returns ctx.Err().
+func (p *ReportPoller) Run(ctx context.Context) error {
+	timer := time.NewTimer(time.Hour)
+	defer timer.Stop()
+	for {
+		next, ok := p.next()
+		if ok {
+			timer.Reset(time.Until(next))
+		} else {
+			timer.Stop()
+		}
+		select {
+		case <-timer.C:
+			err := p.poll(ctx)
+			if err != nil {
+				return err
+			}
+		case <-p.wake:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// next returns the earliest time a URR is to be read.
+func (p *ReportPoller) next() (time.Time, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var next time.Time
+	for _, u := range p.urrs {
+		if at := u.at(); next.IsZero() || at.Before(next) {
+			next = at
+		}
+	}
+	return next, !next.IsZero()
+}
+
+// poll reads the URRs due within the window and delivers their reports.
+func (p *ReportPoller) poll(ctx context.Context) error {
+	now := time.Now()
+	batch := make(map[URRKey]*polledURR)
+	var oids []OID
+	p.mu.Lock()
+	for k, u := range p.urrs {
+		if !u.at().After(now.Add(p.opts.Window)) {
+			batch[k] = u
+			oids = append(oids, k.OID())
+		}
+	}
+	p.mu.Unlock()
+	if len(oids) == 0 {
+		return nil
+	}
+	slices.SortFunc(oids, func(a, b OID) int {
+		return cmp.Or(cmp.Compare(a[0], b[0]), cmp.Compare(a[1], b[1]))
+	})
+
+	results := p.read(ctx, oids)
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	type failure struct {
+		oid OID
+		err error
+	}
+	var reports []USAReport
+	var failures []failure
+	now = time.Now()
+	p.mu.Lock()
+	for _, oid := range oids {
+		k := urrKey(oid)
+		u := batch[k]
+		if p.urrs[k] != u {
+			// removed or added again meanwhile
+			continue
+		}
+		r := results[k]
+		switch {
+		case r.Err == nil:
+			reports = append(reports, r.Reports...)
+			u.advance(now)
+		case errors.Is(r.Err, ErrRuleNotFound):
+			delete(p.urrs, k)
+			failures = append(failures, failure{oid, r.Err})
+		case u.retries < p.opts.Retries:
+			u.retries++
+			u.retry = now.Add(p.opts.RetryDelay)
+		default:
+			u.advance(now)
+			failures = append(failures, failure{oid, r.Err})
+		}
+	}
+	p.mu.Unlock()
+
+	for _, r := range reports {
+		if p.opts.OnReport != nil {
+			p.opts.OnReport(r)
+			continue
+		}
+		select {
+		case p.ch <- r:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	if p.opts.OnError != nil {
+		for _, f := range failures {
+			p.opts.OnError(f.oid, f.err)
+		}
+	}
+	return nil
+}
+
+// read reads the reports of oids with one multi-report call. URRs reported
+// missing by it, which may also fail the whole request, and all of them if
+// the module lacks CMD_GET_MULTI_REPORTS, are read one by one. If the call
+// failed before sending any request, all of them fail with its error.
+func (p *ReportPoller) read(ctx context.Context, oids []OID) map[URRKey]ReportResult {
+	results, err := p.multi(ctx, oids)
+	if results == nil {
+		if errors.Is(err, errors.ErrUnsupported) {
+			err = ErrRuleNotFound
+		}
+		results = make(map[URRKey]ReportResult)
+		for _, oid := range oids {
+			results[urrKey(oid)] = ReportResult{Err: err}
+		}
+	} else if len(oids) == 1 {
+		return results
+	}
+	for _, oid := range oids {
+		k := urrKey(oid)
+		if !errors.Is(results[k].Err, ErrRuleNotFound) {
+			continue
+		}
+		rs, err := p.single(ctx, oid)
+		results[k] = ReportResult{Reports: rs, Err: err}
+	}
+	return results
+}
@@ -0,0 +1,241 @@
+package gtp5gnl
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeReports answers multi-report calls with one report per URR, or with
+// the error set for it.
+type fakeReports struct {
+	mu    sync.Mutex
+	calls [][]OID
+	errs  map[URRKey][]error
+}
+
+func (f *fakeReports) multi(ctx context.Context, oids []OID) (map[URRKey]ReportResult, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, oids)
+	m := make(map[URRKey]ReportResult)
+	for _, oid := range oids {
+		k := urrKey(oid)
+		if errs := f.errs[k]; len(errs) > 0 {
+			f.errs[k] = errs[1:]
+			m[k] = ReportResult{Err: errs[0]}
+			continue
+		}
+		m[k] = ReportResult{Reports: []USAReport{{SEID: k.SEID, URRID: k.URRID}}}
+	}
+	return m, nil
+}
+
+func (f *fakeReports) single(ctx context.Context, oid OID) ([]USAReport, error) {
+	m, _ := f.multi(ctx, []OID{oid})
+	r := m[urrKey(oid)]
+	return r.Reports, r.Err
+}
+
+func newFakePoller(f *fakeReports, opts *ReportPollerOptions) *ReportPoller {
+	p := NewReportPoller(nil, nil, opts)
+	p.multi = f.multi
+	p.single = f.single
+	return p
+}
+
+func recvReport(t *testing.T, p *ReportPoller) USAReport {
+	t.Helper()
+	select {
+	case r := <-p.Reports():
+		return r
+	case <-time.After(time.Second):
+		t.Fatal("timeout")
+		return USAReport{}
+	}
+}
+
+func TestReportPollerBatch(t *testing.T) {
+	f := &fakeReports{}
+	p := newFakePoller(f, &ReportPollerOptions{Window: 20 * time.Millisecond})
+	for id := uint64(1); id <= 3; id++ {
+		err := p.Add(OID{1, id}, 50*time.Millisecond)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go p.Run(ctx)
+
+	seen := make(map[uint32]bool)
+	for range 3 {
+		seen[recvReport(t, p).URRID] = true
+	}
+	if len(seen) != 3 {
+		t.Errorf("want reports of 3 URRs, got %v", seen)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.calls) != 1 || len(f.calls[0]) != 3 {
+		t.Errorf("want one call for 3 URRs, got %v", f.calls)
+	}
+}
+
+func TestReportPollerRetry(t *testing.T) {
+	f := &fakeReports{errs: map[URRKey][]error{
+		{1, 1}: {errors.New("busy"), errors.New("busy")},
+		{1, 2}: {errors.New("busy"), errors.New("busy")},
+	}}
+	var mu sync.Mutex
+	var failed []OID
+	p := newFakePoller(f, &ReportPollerOptions{
+		Retries:    1,
+		RetryDelay: 10 * time.Millisecond,
+		OnError: func(oid OID, err error) {
+			mu.Lock()
+			failed = append(failed, oid)
+			mu.Unlock()
+		},
+	})
+	p.Add(OID{1, 1}, 30*time.Millisecond)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go p.Run(ctx)
+
+	// two failures use up the retry; the next period succeeds
+	r := recvReport(t, p)
+	if r.URRID != 1 {
+		t.Errorf("want report of URR 1, got %+v", r)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(failed) != 1 || !failed[0].Equal(OID{1, 1}) {
+		t.Errorf("want one failure of oid(1, 1), got %v", failed)
+	}
+}
+
+func TestReportPollerNotFound(t *testing.T) {
+	f := &fakeReports{errs: map[URRKey][]error{
+		{1, 2}: {ErrRuleNotFound, ErrRuleNotFound},
+	}}
+	errc := make(chan error, 1)
+	p := newFakePoller(f, &ReportPollerOptions{
+		OnError: func(oid OID, err error) { errc <- err },
+	})
+	p.Add(OID{1, 1}, 20*time.Millisecond)
+	p.Add(OID{1, 2}, 20*time.Millisecond)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go p.Run(ctx)
+
+	if r := recvReport(t, p); r.URRID != 1 {
+		t.Errorf("want report of URR 1, got %+v", r)
+	}
+	select {
+	case err := <-errc:
+		if !errors.Is(err, ErrRuleNotFound) {
+			t.Errorf("want ErrRuleNotFound, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timeout")
+	}
+	if n := p.Len(); n != 1 {
+		t.Errorf("want 1 URR left, got %v", n)
+	}
+}
+
+func TestReportPollerNoResults(t *testing.T) {
+	errNoSend := errors.New("not sent")
+	errc := make(chan error, 2)
+	p := newFakePoller(&fakeReports{}, &ReportPollerOptions{
+		Retries: -1,
+		OnError: func(oid OID, err error) { errc <- err },
+	})
+	p.multi = func(ctx context.Context, oids []OID) (map[URRKey]ReportResult, error) {
+		return nil, errNoSend
+	}
+	p.Add(OID{1, 1}, 20*time.Millisecond)
+	p.Add(OID{1, 2}, 20*time.Millisecond)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go p.Run(ctx)
+
+	for range 2 {
+		select {
+		case err := <-errc:
+			if !errors.Is(err, errNoSend) {
+				t.Errorf("want %v, got %v", errNoSend, err)
+			}
+		case r := <-p.Reports():
+			t.Fatalf("got report without results: %+v", r)
+		case <-time.After(time.Second):
+			t.Fatal("timeout")
+		}
+	}
+}
+
+func TestReportPollerRemove(t *testing.T) {
+	f := &fakeReports{}
+	p := newFakePoller(f, nil)
+	started := make(chan struct{})
+	resume := make(chan struct{})
+	p.multi = func(ctx context.Context, oids []OID) (map[URRKey]ReportResult, error) {
+		close(started)
+		<-resume
+		return f.multi(ctx, oids)
+	}
+	p.Add(OID{1, 1}, 10*time.Millisecond)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error)
+	go func() { done <- p.Run(ctx) }()
+
+	<-started
+	p.Remove(OID{1, 1})
+	close(resume)
+	select {
+	case r := <-p.Reports():
+		t.Errorf("got report of removed URR: %+v", r)
+	case <-time.After(50 * time.Millisecond):
+	}
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("want context.Canceled, got %v", err)
+	}
+}
+
+func TestPolledURRAdvance(t *testing.T) {
+	start := time.Now()
+	u := &polledURR{period: time.Second, due: start, retries: 1, retry: start}
+
+	u.advance(start.Add(100 * time.Millisecond))
+	if want := start.Add(time.Second); !u.due.Equal(want) || !u.retry.IsZero() || u.retries != 0 {
+		t.Errorf("want due %v without retry, got %+v", want, u)
+	}
+
+	// periods missed entirely are skipped
+	u.advance(start.Add(3500 * time.Millisecond))
+	if want := start.Add(4 * time.Second); !u.due.Equal(want) {
+		t.Errorf("want due %v, got %v", want, u.due)
+	}
+}
+
+func TestReportPollerAddURR(t *testing.T) {
+	p := NewReportPoller(nil, nil, nil)
+	if err := p.AddURR(1, &URR{ID: 1}); err == nil {
+		t.Error("want error for URR without period")
+	}
+	period := uint32(60)
+	if err := p.AddURR(1, &URR{ID: 1, Period: &period}); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Add(OID{1}, time.Second); err == nil {
+		t.Error("want error for oid without SEID")
+	}
+	if n := p.Len(); n != 1 {
+		t.Errorf("want 1 URR, got %v", n)
+	}
+}
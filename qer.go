@@ -1,6 +1,7 @@
 package gtp5gnl
 
 import (
+	"context"
 	"fmt"
 	"syscall"
 
@@ -32,9 +33,13 @@ func CreateQER(c *Client, link *Link, qerid int, attrs []nl.Attr) error {
 }
 
 func CreateQEROID(c *Client, link *Link, oid OID, attrs []nl.Attr) error {
+	return CreateQEROIDContext(context.Background(), c, link, oid, attrs)
+}
+
+func CreateQEROIDContext(ctx context.Context, c *Client, link *Link, oid OID, attrs []nl.Attr) error {
 	flags := syscall.NLM_F_EXCL
 	flags |= syscall.NLM_F_ACK
-	req := nl.NewRequest(c.ID, flags)
+	req := nl.NewRequest(c.familyID(), flags)
 	err := req.Append(genl.Header{Cmd: CMD_ADD_QER})
 	if err != nil {
 		return err
@@ -70,8 +75,8 @@ func CreateQEROID(c *Client, link *Link, oid OID, attrs []nl.Attr) error {
 	if err != nil {
 		return err
 	}
-	_, err = c.Do(req)
-	return err
+	_, err = c.DoContext(ctx, req)
+	return withOID(err, oid)
 }
 
 func UpdateQER(c *Client, link *Link, qerid int, attrs []nl.Attr) error {
@@ -79,9 +84,13 @@ func UpdateQER(c *Client, link *Link, qerid int, attrs []nl.Attr) error {
 }
 
 func UpdateQEROID(c *Client, link *Link, oid OID, attrs []nl.Attr) error {
+	return UpdateQEROIDContext(context.Background(), c, link, oid, attrs)
+}
+
+func UpdateQEROIDContext(ctx context.Context, c *Client, link *Link, oid OID, attrs []nl.Attr) error {
 	flags := syscall.NLM_F_REPLACE
 	flags |= syscall.NLM_F_ACK
-	req := nl.NewRequest(c.ID, flags)
+	req := nl.NewRequest(c.familyID(), flags)
 	err := req.Append(genl.Header{Cmd: CMD_ADD_QER})
 	if err != nil {
 		return err
@@ -117,8 +126,8 @@ func UpdateQEROID(c *Client, link *Link, oid OID, attrs []nl.Attr) error {
 	if err != nil {
 		return err
 	}
-	_, err = c.Do(req)
-	return err
+	_, err = c.DoContext(ctx, req)
+	return withOID(err, oid)
 }
 
 func RemoveQER(c *Client, link *Link, qerid int) error {
@@ -126,9 +135,13 @@ func RemoveQER(c *Client, link *Link, qerid int) error {
 }
 
 func RemoveQEROID(c *Client, link *Link, oid OID) error {
+	return RemoveQEROIDContext(context.Background(), c, link, oid)
+}
+
+func RemoveQEROIDContext(ctx context.Context, c *Client, link *Link, oid OID) error {
 	flags := syscall.NLM_F_EXCL
 	flags |= syscall.NLM_F_ACK
-	req := nl.NewRequest(c.ID, flags)
+	req := nl.NewRequest(c.familyID(), flags)
 	err := req.Append(genl.Header{Cmd: CMD_DEL_QER})
 	if err != nil {
 		return err
@@ -160,8 +173,8 @@ func RemoveQEROID(c *Client, link *Link, oid OID) error {
 			return err
 		}
 	}
-	_, err = c.Do(req)
-	return err
+	_, err = c.DoContext(ctx, req)
+	return withOID(err, oid)
 }
 
 func GetQER(c *Client, link *Link, qerid int) (*QER, error) {
@@ -169,8 +182,12 @@ func GetQER(c *Client, link *Link, qerid int) (*QER, error) {
 }
 
 func GetQEROID(c *Client, link *Link, oid OID) (*QER, error) {
+	return GetQEROIDContext(context.Background(), c, link, oid)
+}
+
+func GetQEROIDContext(ctx context.Context, c *Client, link *Link, oid OID) (*QER, error) {
 	flags := syscall.NLM_F_ACK
-	req := nl.NewRequest(c.ID, flags)
+	req := nl.NewRequest(c.familyID(), flags)
 	err := req.Append(genl.Header{Cmd: CMD_GET_QER})
 	if err != nil {
 		return nil, err
@@ -202,12 +219,12 @@ func GetQEROID(c *Client, link *Link, oid OID) (*QER, error) {
 			return nil, err
 		}
 	}
-	rsps, err := c.Do(req)
+	rsps, err := c.DoContext(ctx, req)
 	if err != nil {
-		return nil, err
+		return nil, withOID(err, oid)
 	}
 	if len(rsps) < 1 {
-		return nil, fmt.Errorf("nil QER of oid(%v)", oid)
+		return nil, notFound(CMD_GET_QER, oid)
 	}
 	qer, err := DecodeQER(rsps[0].Body[genl.SizeofHeader:])
 	if err != nil {
@@ -217,13 +234,17 @@ func GetQEROID(c *Client, link *Link, oid OID) (*QER, error) {
 }
 
 func GetQERAll(c *Client) ([]QER, error) {
+	return GetQERAllContext(context.Background(), c)
+}
+
+func GetQERAllContext(ctx context.Context, c *Client) ([]QER, error) {
 	flags := syscall.NLM_F_DUMP
-	req := nl.NewRequest(c.ID, flags)
+	req := nl.NewRequest(c.familyID(), flags)
 	err := req.Append(genl.Header{Cmd: CMD_GET_QER})
 	if err != nil {
 		return nil, err
 	}
-	rsps, err := c.Do(req)
+	rsps, err := c.DoContext(ctx, req)
 	if err != nil {
 		return nil, err
 	}
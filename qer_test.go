@@ -3,8 +3,6 @@ package gtp5gnl
 import (
 	"encoding/json"
 	"log"
-	"sync"
-	"syscall"
 	"testing"
 
 	"github.com/khirono/go-nl"
@@ -15,31 +13,11 @@ func TestCreateQER(t *testing.T) {
 		t.Skip("skipping testing in short mode")
 	}
 
-	var wg sync.WaitGroup
-	mux, err := nl.NewMux()
-	if err != nil {
-		t.Fatal(err)
-	}
-	defer func() {
-		mux.Close()
-		wg.Wait()
-	}()
-	wg.Add(1)
-	go func() {
-		mux.Serve()
-		wg.Done()
-	}()
-
-	conn, err := nl.Open(syscall.NETLINK_GENERIC)
-	if err != nil {
-		t.Fatal(err)
-	}
-	defer conn.Close()
-
-	c, err := NewClient(conn, mux)
+	c, err := Dial()
 	if err != nil {
 		t.Fatal(err)
 	}
+	defer c.Close()
 
 	link, err := GetLink("upfgtp")
 	if err != nil {
@@ -89,31 +67,11 @@ func TestGetQER(t *testing.T) {
 		t.Skip("skipping testing in short mode")
 	}
 
-	var wg sync.WaitGroup
-	mux, err := nl.NewMux()
-	if err != nil {
-		t.Fatal(err)
-	}
-	defer func() {
-		mux.Close()
-		wg.Wait()
-	}()
-	wg.Add(1)
-	go func() {
-		mux.Serve()
-		wg.Done()
-	}()
-
-	conn, err := nl.Open(syscall.NETLINK_GENERIC)
-	if err != nil {
-		t.Fatal(err)
-	}
-	defer conn.Close()
-
-	c, err := NewClient(conn, mux)
+	c, err := Dial()
 	if err != nil {
 		t.Fatal(err)
 	}
+	defer c.Close()
 
 	link, err := GetLink("upfgtp")
 	if err != nil {
@@ -137,31 +95,11 @@ func TestGetQERAll(t *testing.T) {
 		t.Skip("skipping testing in short mode")
 	}
 
-	var wg sync.WaitGroup
-	mux, err := nl.NewMux()
-	if err != nil {
-		t.Fatal(err)
-	}
-	defer func() {
-		mux.Close()
-		wg.Wait()
-	}()
-	wg.Add(1)
-	go func() {
-		mux.Serve()
-		wg.Done()
-	}()
-
-	conn, err := nl.Open(syscall.NETLINK_GENERIC)
-	if err != nil {
-		t.Fatal(err)
-	}
-	defer conn.Close()
-
-	c, err := NewClient(conn, mux)
+	c, err := Dial()
 	if err != nil {
 		t.Fatal(err)
 	}
+	defer c.Close()
 
 	qers, err := GetQERAll(c)
 	if err != nil {
@@ -180,31 +118,11 @@ func TestRemoveQER(t *testing.T) {
 		t.Skip("skipping testing in short mode")
 	}
 
-	var wg sync.WaitGroup
-	mux, err := nl.NewMux()
-	if err != nil {
-		t.Fatal(err)
-	}
-	defer func() {
-		mux.Close()
-		wg.Wait()
-	}()
-	wg.Add(1)
-	go func() {
-		mux.Serve()
-		wg.Done()
-	}()
-
-	conn, err := nl.Open(syscall.NETLINK_GENERIC)
-	if err != nil {
-		t.Fatal(err)
-	}
-	defer conn.Close()
-
-	c, err := NewClient(conn, mux)
+	c, err := Dial()
 	if err != nil {
 		t.Fatal(err)
 	}
+	defer c.Close()
 
 	link, err := GetLink("upfgtp")
 	if err != nil {
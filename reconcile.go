@@ -0,0 +1,143 @@
+package gtp5gnl
+
+import (
+	"context"
+)
+
+// RuleChange identifies one rule touched by a ReconcilePlan.
+type RuleChange struct {
+	Kind string // "PDR", "FAR", "QER", "URR" or "BAR"
+	OID  OID
+}
+
+// ReconcilePlan is the minimal set of changes that makes the rules in the
+// kernel match an intended set of sessions.
+type ReconcilePlan struct {
+	Creates []RuleChange
+	Updates []RuleChange
+	Removes []RuleChange
+
+	p rulePlan
+}
+
+// PlanReconcile compares the rules dumped from the kernel with the rules of
+// want. Rules in the kernel that belong to no session of want are removed;
+// rules whose attributes differ are updated; missing rules are created.
+//
+// The kernel dumps the rules of every gtp5g link, so want must describe all
+// of them; PlanReconcile is meant for hosts with a single gtp5g link.
+func PlanReconcile(c *Client, want []Session) (*ReconcilePlan, error) {
+	return PlanReconcileContext(context.Background(), c, want)
+}
+
+func PlanReconcileContext(ctx context.Context, c *Client, want []Session) (*ReconcilePlan, error) {
+	cur, err := kernelRules(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	var next []rule
+	for i := range want {
+		rs, err := want[i].rules()
+		if err != nil {
+			return nil, err
+		}
+		next = append(next, rs...)
+	}
+	p := diffRules(cur, next)
+	plan := &ReconcilePlan{p: p}
+	plan.Creates = ruleChanges(p.creates)
+	plan.Updates = ruleChanges(p.updates)
+	plan.Removes = ruleChanges(p.removes)
+	return plan, nil
+}
+
+// Empty reports whether the kernel already matches the intended sessions.
+func (plan *ReconcilePlan) Empty() bool {
+	return plan.p.empty()
+}
+
+// Apply sends the changes of plan to the kernel in dependency order. It
+// stops at the first error without undoing the changes made so far; running
+// Reconcile again continues from there. The usage reports returned by
+// updated and removed URRs are returned.
+func (plan *ReconcilePlan) Apply(c *Client, link *Link) ([]USAReport, error) {
+	return plan.ApplyContext(context.Background(), c, link)
+}
+
+func (plan *ReconcilePlan) ApplyContext(ctx context.Context, c *Client, link *Link) ([]USAReport, error) {
+	return plan.p.apply(directWriter{ctx: ctx, c: c, link: link})
+}
+
+// Reconcile makes the rules in the kernel match want without touching the
+// rules that already do. See PlanReconcile.
+func Reconcile(c *Client, link *Link, want []Session) (*ReconcilePlan, []USAReport, error) {
+	return ReconcileContext(context.Background(), c, link, want)
+}
+
+func ReconcileContext(ctx context.Context, c *Client, link *Link, want []Session) (*ReconcilePlan, []USAReport, error) {
+	plan, err := PlanReconcileContext(ctx, c, want)
+	if err != nil {
+		return nil, nil, err
+	}
+	reports, err := plan.ApplyContext(ctx, c, link)
+	return plan, reports, err
+}
+
+func ruleChanges(rs []rule) []RuleChange {
+	var changes []RuleChange
+	for _, r := range rs {
+		changes = append(changes, RuleChange{Kind: r.kind.String(), OID: r.oid})
+	}
+	return changes
+}
+
+func kernelRules(ctx context.Context, c *Client) ([]rule, error) {
+	var rs []rule
+	bars, err := GetBARAllContext(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	for i := range bars {
+		rs = append(rs, barRule(seidOf(bars[i].SEID), &bars[i]))
+	}
+	fars, err := GetFARAllContext(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	for i := range fars {
+		rs = append(rs, farRule(seidOf(fars[i].SEID), &fars[i]))
+	}
+	qers, err := GetQERAllContext(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	for i := range qers {
+		rs = append(rs, qerRule(seidOf(qers[i].SEID), &qers[i]))
+	}
+	urrs, err := GetURRAllContext(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	for i := range urrs {
+		rs = append(rs, urrRule(seidOf(urrs[i].SEID), &urrs[i]))
+	}
+	pdrs, err := GetPDRAllContext(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	for i := range pdrs {
+		r, err := pdrRule(seidOf(pdrs[i].SEID), &pdrs[i])
+		if err != nil {
+			return nil, err
+		}
+		rs = append(rs, r)
+	}
+	return rs, nil
+}
+
+func seidOf(seid *uint64) uint64 {
+	if seid == nil {
+		return 0
+	}
+	return *seid
+}
@@ -0,0 +1,48 @@
+package gtp5gnl
+
+import (
+	"testing"
+)
+
+func TestReconcile(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping testing in short mode")
+	}
+
+	c, err := Dial()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	link, err := GetLink("upfgtp")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	farid := uint32(1)
+	want := []Session{
+		{
+			SEID: 30,
+			PDRs: []PDR{{ID: 1, FARID: &farid}},
+			FARs: []FAR{{ID: 1, Action: 2}},
+		},
+	}
+	_, _, err = Reconcile(c, link, want)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plan, err := PlanReconcile(c, want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !plan.Empty() {
+		t.Errorf("want empty plan; but got %+v\n", plan)
+	}
+
+	_, _, err = Reconcile(c, link, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
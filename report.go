@@ -1,7 +1,10 @@
 package gtp5gnl
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"sync"
 	"syscall"
 
 	"github.com/khirono/go-genl"
@@ -13,10 +16,18 @@ func GetReport(c *Client, link *Link, urrid uint64, seid uint64) ([]USAReport, e
 }
 
 func GetUsageStatistic(c *Client, link *Link) (*UsageStatistic, error) {
+	return GetUsageStatisticContext(context.Background(), c, link)
+}
+
+func GetUsageStatisticContext(ctx context.Context, c *Client, link *Link) (*UsageStatistic, error) {
+	err := c.require("CMD_GET_USAGE_STATISTIC", c.Capabilities().UsageStatistic)
+	if err != nil {
+		return nil, err
+	}
 	flags := syscall.NLM_F_ACK
-	req := nl.NewRequest(c.ID, flags)
+	req := nl.NewRequest(c.familyID(), flags)
 
-	err := req.Append(genl.Header{Cmd: CMD_GET_USAGE_STATISTIC})
+	err = req.Append(genl.Header{Cmd: CMD_GET_USAGE_STATISTIC})
 	if err != nil {
 		return nil, err
 	}
@@ -31,7 +42,7 @@ func GetUsageStatistic(c *Client, link *Link) (*UsageStatistic, error) {
 		return nil, err
 	}
 
-	rsps, err := c.Do(req)
+	rsps, err := c.DoContext(ctx, req)
 	if err != nil {
 		return nil, err
 	}
@@ -46,8 +57,12 @@ func GetUsageStatistic(c *Client, link *Link) (*UsageStatistic, error) {
 }
 
 func GetReportOID(c *Client, link *Link, oid OID) ([]USAReport, error) {
+	return GetReportOIDContext(context.Background(), c, link, oid)
+}
+
+func GetReportOIDContext(ctx context.Context, c *Client, link *Link, oid OID) ([]USAReport, error) {
 	flags := syscall.NLM_F_ACK
-	req := nl.NewRequest(c.ID, flags)
+	req := nl.NewRequest(c.familyID(), flags)
 	err := req.Append(genl.Header{Cmd: CMD_GET_REPORT})
 	if err != nil {
 		return nil, err
@@ -79,12 +94,12 @@ func GetReportOID(c *Client, link *Link, oid OID) ([]USAReport, error) {
 			return nil, err
 		}
 	}
-	rsps, err := c.Do(req)
+	rsps, err := c.DoContext(ctx, req)
 	if err != nil {
-		return nil, err
+		return nil, withOID(err, oid)
 	}
 	if len(rsps) < 1 {
-		return nil, fmt.Errorf("nil Report of oid(%v)", oid)
+		return nil, notFound(CMD_GET_REPORT, oid)
 	}
 	reports, err := DecodeAllUSAReports(rsps[0].Body[genl.SizeofHeader:])
 	if err != nil {
@@ -105,11 +120,185 @@ func GetMultiReports(c *Client, link *Link, lSeidUrridsMap map[uint64][]uint32)
 }
 
 func GetMultiReportsOID(c *Client, link *Link, oids []OID) ([]USAReport, error) {
+	return GetMultiReportsOIDContext(context.Background(), c, link, oids)
+}
+
+// GetMultiReportsOIDContext reads the usage reports of oids, split into
+// requests of MaxNetlinkUsageReportNum URRs so that each reply fits in one
+// netlink message. The requests are sent one after another and stop at the
+// first failure, which is returned with the reports of the requests sent
+// before it. Use GetMultiReportsOIDResults to see which URRs were not
+// reported.
+func GetMultiReportsOIDContext(ctx context.Context, c *Client, link *Link, oids []OID) ([]USAReport, error) {
+	err := checkMultiReportsOIDs(oids)
+	if err != nil {
+		return nil, err
+	}
+	var reports []USAReport
+	for _, chunk := range chunkOIDs(oids, MaxNetlinkUsageReportNum()) {
+		rs, err := getMultiReports(ctx, c, link, chunk)
+		if err != nil {
+			return reports, err
+		}
+		reports = append(reports, rs...)
+	}
+	return reports, nil
+}
+
+// URRKey identifies a URR in the results of GetMultiReportsOIDResults.
+type URRKey struct {
+	SEID  uint64
+	URRID uint32
+}
+
+func urrKey(oid OID) URRKey {
+	seid, _ := oid.SEID()
+	id, _ := oid.ID()
+	return URRKey{SEID: seid, URRID: uint32(id)}
+}
+
+func (k URRKey) OID() OID {
+	return OID{k.SEID, uint64(k.URRID)}
+}
+
+// ReportResult is the outcome of GetMultiReportsOIDResults for one URR.
+// Err is the error of the request that carried the URR, or an error
+// matching ErrRuleNotFound if the kernel returned no report for it.
+type ReportResult struct {
+	Reports []USAReport
+	Err     error
+}
+
+// MultiReportsOptions tunes GetMultiReportsOIDResults.
+type MultiReportsOptions struct {
+	// ChunkSize is the number of URRs per request. Zero, or more than
+	// MaxNetlinkUsageReportNum, means MaxNetlinkUsageReportNum.
+	ChunkSize int
+	// Clients are used, besides c, to send requests concurrently. A
+	// Client sends one request at a time, so each request in flight
+	// needs its own Client.
+	Clients []*Client
+}
+
+func GetMultiReportsOIDResults(c *Client, link *Link, oids []OID, opts *MultiReportsOptions) (map[URRKey]ReportResult, error) {
+	return GetMultiReportsOIDResultsContext(context.Background(), c, link, oids, opts)
+}
+
+// GetMultiReportsOIDResultsContext reads the usage reports of oids in
+// chunks like GetMultiReportsOIDContext, but goes on after a failed request
+// and returns a result for each of oids, and for any other URR the kernel
+// reported. The error joins the errors of the failed requests.
+func GetMultiReportsOIDResultsContext(ctx context.Context, c *Client, link *Link, oids []OID, opts *MultiReportsOptions) (map[URRKey]ReportResult, error) {
+	err := c.require("CMD_GET_MULTI_REPORTS", c.Capabilities().MultiReports)
+	if err != nil {
+		return nil, err
+	}
+	err = checkMultiReportsOIDs(oids)
+	if err != nil {
+		return nil, err
+	}
+	n := MaxNetlinkUsageReportNum()
+	var clients []*Client
+	if opts != nil {
+		if opts.ChunkSize > 0 {
+			n = min(opts.ChunkSize, n)
+		}
+		clients = opts.Clients
+	}
+	clients = append([]*Client{c}, clients...)
+	chunks := chunkOIDs(oids, n)
+
+	results := make([]chunkReports, len(chunks))
+	next := make(chan int, len(chunks))
+	for i := range chunks {
+		next <- i
+	}
+	close(next)
+	var wg sync.WaitGroup
+	for _, c := range clients[:min(len(clients), len(chunks))] {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				rs, err := getMultiReports(ctx, c, link, chunks[i])
+				results[i] = chunkReports{chunks[i], rs, err}
+			}
+		}()
+	}
+	wg.Wait()
+	return mergeChunkReports(results)
+}
+
+// chunkReports is the reply to the request for one chunk of OIDs.
+type chunkReports struct {
+	oids    []OID
+	reports []USAReport
+	err     error
+}
+
+func mergeChunkReports(results []chunkReports) (map[URRKey]ReportResult, error) {
+	m := make(map[URRKey]ReportResult)
+	var errs []error
+	for _, r := range results {
+		if r.err != nil {
+			errs = append(errs, r.err)
+			for _, oid := range r.oids {
+				m[urrKey(oid)] = ReportResult{Err: r.err}
+			}
+			continue
+		}
+		for _, report := range r.reports {
+			k := URRKey{SEID: report.SEID, URRID: report.URRID}
+			res := m[k]
+			res.Reports = append(res.Reports, report)
+			m[k] = res
+		}
+		for _, oid := range r.oids {
+			k := urrKey(oid)
+			if _, ok := m[k]; !ok {
+				m[k] = ReportResult{Err: notFound(CMD_GET_MULTI_REPORTS, oid)}
+			}
+		}
+	}
+	return m, errors.Join(errs...)
+}
+
+// chunkOIDs splits oids into slices of at most n OIDs.
+func chunkOIDs(oids []OID, n int) [][]OID {
+	var chunks [][]OID
+	for len(oids) > n {
+		chunks = append(chunks, oids[:n:n])
+		oids = oids[n:]
+	}
+	if len(oids) > 0 {
+		chunks = append(chunks, oids)
+	}
+	return chunks
+}
+
+// checkMultiReportsOIDs checks that each oid has a SEID, which
+// CMD_GET_MULTI_REPORTS needs to find the URR, before any request is sent.
+func checkMultiReportsOIDs(oids []OID) error {
+	for _, oid := range oids {
+		_, ok := oid.SEID()
+		if !ok {
+			return fmt.Errorf("invalid oid: %v", oid)
+		}
+	}
+	return nil
+}
+
+func getMultiReports(ctx context.Context, c *Client, link *Link, oids []OID) ([]USAReport, error) {
 	var attrs []nl.Attr
 
+	err := c.require("CMD_GET_MULTI_REPORTS", c.Capabilities().MultiReports)
+	if err != nil {
+		return nil, err
+	}
+
 	flags := syscall.NLM_F_ACK
-	req := nl.NewRequest(c.ID, flags)
-	err := req.Append(genl.Header{Cmd: CMD_GET_MULTI_REPORTS})
+	req := nl.NewRequest(c.familyID(), flags)
+	err = req.Append(genl.Header{Cmd: CMD_GET_MULTI_REPORTS})
 	if err != nil {
 		return nil, err
 	}
@@ -134,29 +323,30 @@ func GetMultiReportsOID(c *Client, link *Link, oids []OID) ([]USAReport, error)
 		}
 
 		seid, ok := oid.SEID()
-		if ok {
-			attrs = append(attrs, nl.Attr{
-				Type: URR_MULTI_SEID_URRID,
-				Value: nl.AttrList{
-					{
-						Type:  URR_ID,
-						Value: nl.AttrU32(urrid),
-					},
-					{
-						Type:  URR_SEID,
-						Value: nl.AttrU64(seid),
-					},
+		if !ok {
+			return nil, fmt.Errorf("invalid oid: %v", oid)
+		}
+		attrs = append(attrs, nl.Attr{
+			Type: URR_MULTI_SEID_URRID,
+			Value: nl.AttrList{
+				{
+					Type:  URR_ID,
+					Value: nl.AttrU32(urrid),
+				},
+				{
+					Type:  URR_SEID,
+					Value: nl.AttrU64(seid),
 				},
 			},
-			)
-		}
+		},
+		)
 	}
 	err = req.Append(nl.AttrList(attrs))
 	if err != nil {
 		return nil, err
 	}
 
-	rsps, err := c.Do(req)
+	rsps, err := c.DoContext(ctx, req)
 	if err != nil {
 		return nil, err
 	}
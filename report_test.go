@@ -0,0 +1,101 @@
+package gtp5gnl
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestChunkOIDs(t *testing.T) {
+	var oids []OID
+	for i := range 5 {
+		oids = append(oids, OID{1, uint64(i)})
+	}
+	chunks := chunkOIDs(oids, 2)
+	if len(chunks) != 3 || len(chunks[0]) != 2 || len(chunks[2]) != 1 {
+		t.Fatalf("got %v", chunks)
+	}
+	chunks[0] = append(chunks[0], OID{2, 2})
+	if !oids[2].Equal(OID{1, 2}) {
+		t.Error("appending to a chunk overwrote the next one")
+	}
+	if chunks := chunkOIDs(nil, 2); len(chunks) != 0 {
+		t.Errorf("want no chunk, got %v", chunks)
+	}
+}
+
+func TestMergeChunkReports(t *testing.T) {
+	failed := errors.New("failed")
+	m, err := mergeChunkReports([]chunkReports{
+		{
+			oids: []OID{{1, 1}, {1, 2}, {2, 1}},
+			reports: []USAReport{
+				{SEID: 1, URRID: 1, URSEQN: 1},
+				{SEID: 2, URRID: 1},
+				{SEID: 1, URRID: 1, URSEQN: 2},
+			},
+		},
+		{
+			oids: []OID{{3, 1}},
+			err:  failed,
+		},
+	})
+	if !errors.Is(err, failed) {
+		t.Errorf("want failed, got %v", err)
+	}
+	want := []USAReport{{SEID: 1, URRID: 1, URSEQN: 1}, {SEID: 1, URRID: 1, URSEQN: 2}}
+	if r := m[URRKey{1, 1}]; r.Err != nil || !reflect.DeepEqual(r.Reports, want) {
+		t.Errorf("URR 1/1: got %+v", r)
+	}
+	if r := m[URRKey{2, 1}]; r.Err != nil || len(r.Reports) != 1 {
+		t.Errorf("URR 2/1: got %+v", r)
+	}
+	if r := m[URRKey{1, 2}]; !errors.Is(r.Err, ErrRuleNotFound) {
+		t.Errorf("URR 1/2: want ErrRuleNotFound, got %+v", r)
+	}
+	if r := m[URRKey{3, 1}]; !errors.Is(r.Err, failed) {
+		t.Errorf("URR 3/1: want failed, got %+v", r)
+	}
+	if len(m) != 4 {
+		t.Errorf("want 4 results, got %v", len(m))
+	}
+}
+
+func TestGetMultiReportsOIDResultsCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	newClient := func() *Client { return &Client{sem: make(chan struct{}, 1)} }
+	link := &Link{Name: "upfgtp", Index: 1}
+	oids := []OID{{1, 1}, {1, 2}, {1, 3}}
+	opts := &MultiReportsOptions{ChunkSize: 1, Clients: []*Client{newClient()}}
+	m, err := GetMultiReportsOIDResultsContext(ctx, newClient(), link, oids, opts)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("want context.Canceled, got %v", err)
+	}
+	for _, oid := range oids {
+		r, ok := m[urrKey(oid)]
+		if !ok || !errors.Is(r.Err, context.Canceled) {
+			t.Errorf("oid(%v): want context.Canceled, got %+v", oid, r)
+		}
+	}
+}
+
+func TestGetMultiReportsOIDWithoutSEID(t *testing.T) {
+	// a request would fail with context.Canceled
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	c := &Client{sem: make(chan struct{}, 1)}
+	link := &Link{Name: "upfgtp", Index: 1}
+	oids := []OID{{1, 1}, {2}}
+	_, err := GetMultiReportsOIDContext(ctx, c, link, oids)
+	if err == nil || errors.Is(err, context.Canceled) {
+		t.Errorf("want invalid oid, got %v", err)
+	}
+	_, err = GetMultiReportsOIDResultsContext(ctx, c, link, oids, nil)
+	if err == nil || errors.Is(err, context.Canceled) {
+		t.Errorf("want invalid oid, got %v", err)
+	}
+}
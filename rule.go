@@ -0,0 +1,396 @@
+package gtp5gnl
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"slices"
+	"syscall"
+
+	"github.com/khirono/go-genl"
+	"github.com/khirono/go-nl"
+)
+
+type ruleKind int
+
+// Rule kinds in install order: a rule only refers to kinds listed before it
+// (FAR -> BAR, PDR -> FAR/QER/URR).
+const (
+	ruleBAR ruleKind = iota
+	ruleFAR
+	ruleQER
+	ruleURR
+	rulePDR
+)
+
+func (k ruleKind) String() string {
+	switch k {
+	case ruleBAR:
+		return "BAR"
+	case ruleFAR:
+		return "FAR"
+	case ruleQER:
+		return "QER"
+	case ruleURR:
+		return "URR"
+	case rulePDR:
+		return "PDR"
+	default:
+		return fmt.Sprintf("ruleKind(%d)", int(k))
+	}
+}
+
+// rule is an encoded PDR/FAR/QER/URR/BAR ready to be sent to the kernel.
+type rule struct {
+	kind  ruleKind
+	oid   OID
+	attrs []nl.Attr
+}
+
+type ruleKey struct {
+	kind ruleKind
+	seid uint64
+	id   int
+}
+
+func (r rule) key() ruleKey {
+	seid, _ := r.oid.SEID()
+	id, _ := r.oid.ID()
+	return ruleKey{kind: r.kind, seid: seid, id: id}
+}
+
+func pdrRule(seid uint64, pdr *PDR) (rule, error) {
+	oid := OID{seid, uint64(pdr.ID)}
+	attrs, err := EncodePDR(pdr)
+	if err != nil {
+		return rule{}, fmt.Errorf("PDR oid(%v): %w", oid, err)
+	}
+	return rule{kind: rulePDR, oid: oid, attrs: attrs}, nil
+}
+
+func farRule(seid uint64, far *FAR) rule {
+	return rule{kind: ruleFAR, oid: OID{seid, uint64(far.ID)}, attrs: EncodeFAR(far)}
+}
+
+func qerRule(seid uint64, qer *QER) rule {
+	return rule{kind: ruleQER, oid: OID{seid, uint64(qer.ID)}, attrs: EncodeQER(qer)}
+}
+
+func urrRule(seid uint64, urr *URR) rule {
+	return rule{kind: ruleURR, oid: OID{seid, uint64(urr.ID)}, attrs: EncodeURR(urr)}
+}
+
+func barRule(seid uint64, bar *BAR) rule {
+	return rule{kind: ruleBAR, oid: OID{seid, uint64(bar.ID)}, attrs: EncodeBAR(bar)}
+}
+
+func createRule(ctx context.Context, c *Client, link *Link, r rule) error {
+	switch r.kind {
+	case rulePDR:
+		return CreatePDROIDContext(ctx, c, link, r.oid, r.attrs)
+	case ruleFAR:
+		return CreateFAROIDContext(ctx, c, link, r.oid, r.attrs)
+	case ruleQER:
+		return CreateQEROIDContext(ctx, c, link, r.oid, r.attrs)
+	case ruleURR:
+		return CreateURROIDContext(ctx, c, link, r.oid, r.attrs)
+	case ruleBAR:
+		return CreateBAROIDContext(ctx, c, link, r.oid, r.attrs)
+	default:
+		return fmt.Errorf("unknown rule kind: %v", r.kind)
+	}
+}
+
+func updateRule(ctx context.Context, c *Client, link *Link, r rule) ([]USAReport, error) {
+	switch r.kind {
+	case rulePDR:
+		return nil, UpdatePDROIDContext(ctx, c, link, r.oid, r.attrs)
+	case ruleFAR:
+		return nil, UpdateFAROIDContext(ctx, c, link, r.oid, r.attrs)
+	case ruleQER:
+		return nil, UpdateQEROIDContext(ctx, c, link, r.oid, r.attrs)
+	case ruleURR:
+		return UpdateURROIDContext(ctx, c, link, r.oid, r.attrs)
+	case ruleBAR:
+		return nil, UpdateBAROIDContext(ctx, c, link, r.oid, r.attrs)
+	default:
+		return nil, fmt.Errorf("unknown rule kind: %v", r.kind)
+	}
+}
+
+func removeRule(ctx context.Context, c *Client, link *Link, kind ruleKind, oid OID) ([]USAReport, error) {
+	switch kind {
+	case rulePDR:
+		return nil, RemovePDROIDContext(ctx, c, link, oid)
+	case ruleFAR:
+		return nil, RemoveFAROIDContext(ctx, c, link, oid)
+	case ruleQER:
+		return nil, RemoveQEROIDContext(ctx, c, link, oid)
+	case ruleURR:
+		return RemoveURROIDContext(ctx, c, link, oid)
+	case ruleBAR:
+		return nil, RemoveBAROIDContext(ctx, c, link, oid)
+	default:
+		return nil, fmt.Errorf("unknown rule kind: %v", kind)
+	}
+}
+
+// getRule reads the rule back from the kernel so that it can be created or
+// updated again. The attributes are kept as the kernel sent them, including
+// those the decoders do not know. Only the ID and SEID, which the OID
+// carries, and the RELATED_TO_PDR lists the kernel maintains are left out.
+func getRule(ctx context.Context, c *Client, link *Link, kind ruleKind, oid OID) (rule, error) {
+	r := rule{kind: kind, oid: oid}
+	id, ok := oid.ID()
+	if !ok {
+		return r, fmt.Errorf("invalid oid: %v", oid)
+	}
+	var cmd int
+	var idAttr nl.Attr
+	var seidType uint16
+	var omit []int
+	switch kind {
+	case rulePDR:
+		cmd = CMD_GET_PDR
+		idAttr = nl.Attr{Type: PDR_ID, Value: nl.AttrU16(id)}
+		seidType = PDR_SEID
+		omit = []int{PDR_ID, PDR_SEID}
+	case ruleFAR:
+		cmd = CMD_GET_FAR
+		idAttr = nl.Attr{Type: FAR_ID, Value: nl.AttrU32(id)}
+		seidType = FAR_SEID
+		omit = []int{FAR_ID, FAR_SEID, FAR_RELATED_TO_PDR}
+	case ruleQER:
+		cmd = CMD_GET_QER
+		idAttr = nl.Attr{Type: QER_ID, Value: nl.AttrU32(id)}
+		seidType = QER_SEID
+		omit = []int{QER_ID, QER_SEID, QER_RELATED_TO_PDR}
+	case ruleURR:
+		cmd = CMD_GET_URR
+		idAttr = nl.Attr{Type: URR_ID, Value: nl.AttrU32(id)}
+		seidType = URR_SEID
+		omit = []int{URR_ID, URR_SEID}
+	case ruleBAR:
+		cmd = CMD_GET_BAR
+		idAttr = nl.Attr{Type: BAR_ID, Value: nl.AttrU8(id)}
+		seidType = BAR_SEID
+		omit = []int{BAR_ID, BAR_SEID}
+	default:
+		return r, fmt.Errorf("unknown rule kind: %v", kind)
+	}
+	flags := syscall.NLM_F_ACK
+	req := nl.NewRequest(c.familyID(), flags)
+	err := req.Append(genl.Header{Cmd: uint8(cmd)})
+	if err != nil {
+		return r, err
+	}
+	err = req.Append(&nl.AttrList{
+		{
+			Type:  LINK,
+			Value: nl.AttrU32(link.Index),
+		},
+		idAttr,
+	})
+	if err != nil {
+		return r, err
+	}
+	seid, ok := oid.SEID()
+	if ok {
+		err = req.Append(&nl.Attr{
+			Type:  seidType,
+			Value: nl.AttrU64(seid),
+		})
+		if err != nil {
+			return r, err
+		}
+	}
+	rsps, err := c.DoContext(ctx, req)
+	if err != nil {
+		return r, withOID(err, oid)
+	}
+	if len(rsps) < 1 {
+		return r, notFound(cmd, oid)
+	}
+	r.attrs, err = rawAttrs(kind.String(), rsps[0].Body[genl.SizeofHeader:], omit...)
+	return r, err
+}
+
+// rawAttrs splits b into attributes whose types, with their flags, and
+// payloads are kept as received, leaving out the types in omit. name is
+// the path of the errors.
+func rawAttrs(name string, b []byte, omit ...int) ([]nl.Attr, error) {
+	spec := attrSpec{name: name}
+	var attrs []nl.Attr
+	for len(b) > 0 {
+		hdr, n, err := spec.decodeAttrHdr(b)
+		if err != nil {
+			return nil, err
+		}
+		if !slices.Contains(omit, hdr.MaskedType()) {
+			v := make([]byte, int(hdr.Len)-n)
+			copy(v, b[n:hdr.Len])
+			attrs = append(attrs, nl.Attr{Type: hdr.Type, Value: nl.AttrBytes(v)})
+		}
+		b = nextAttr(b, hdr)
+	}
+	return attrs, nil
+}
+
+// normalize returns the attributes of r as the kernel would dump them back:
+// encoded, decoded and encoded again. Rules read from the kernel have been
+// through the decoder once, so comparing the raw encodings would report
+// changes for fields the decoder fills in or drops. If decoding fails the
+// plain encoding is returned.
+func (r rule) normalize() []byte {
+	b := encodeRuleAttrs(r.attrs)
+	var attrs []nl.Attr
+	switch r.kind {
+	case rulePDR:
+		pdr, err := DecodePDR(b)
+		if err != nil {
+			return b
+		}
+		attrs, err = EncodePDR(pdr)
+		if err != nil {
+			return b
+		}
+	case ruleFAR:
+		far, err := DecodeFAR(b)
+		if err != nil {
+			return b
+		}
+		attrs = EncodeFAR(far)
+	case ruleQER:
+		qer, err := DecodeQER(b)
+		if err != nil {
+			return b
+		}
+		attrs = EncodeQER(qer)
+	case ruleURR:
+		urr, err := DecodeURR(b)
+		if err != nil {
+			return b
+		}
+		attrs = EncodeURR(urr)
+	case ruleBAR:
+		bar, err := DecodeBAR(b)
+		if err != nil {
+			return b
+		}
+		attrs = EncodeBAR(bar)
+	default:
+		return b
+	}
+	return encodeRuleAttrs(attrs)
+}
+
+func encodeRuleAttrs(attrs []nl.Attr) []byte {
+	al := nl.AttrList(attrs)
+	b := make([]byte, al.Len())
+	n, _ := al.Encode(b)
+	return b[:n]
+}
+
+// rulePlan is the set of changes that turns one rule set into another.
+type rulePlan struct {
+	creates []rule
+	updates []rule
+	removes []rule
+}
+
+func diffRules(cur, next []rule) rulePlan {
+	var p rulePlan
+	old := make(map[ruleKey]rule, len(cur))
+	for _, r := range cur {
+		old[r.key()] = r
+	}
+	seen := make(map[ruleKey]bool, len(next))
+	for _, r := range next {
+		k := r.key()
+		seen[k] = true
+		o, ok := old[k]
+		switch {
+		case !ok:
+			p.creates = append(p.creates, r)
+		case !bytes.Equal(o.normalize(), r.normalize()):
+			p.updates = append(p.updates, r)
+		}
+	}
+	for _, r := range cur {
+		if !seen[r.key()] {
+			p.removes = append(p.removes, r)
+		}
+	}
+	return p
+}
+
+func (p rulePlan) empty() bool {
+	return len(p.creates) == 0 && len(p.updates) == 0 && len(p.removes) == 0
+}
+
+// ruleWriter sends rule changes to the kernel, either directly or through
+// a Tx.
+type ruleWriter interface {
+	createRule(r rule) error
+	updateRule(r rule) ([]USAReport, error)
+	removeRule(kind ruleKind, oid OID) ([]USAReport, error)
+}
+
+type directWriter struct {
+	ctx  context.Context
+	c    *Client
+	link *Link
+}
+
+func (w directWriter) createRule(r rule) error {
+	return createRule(w.ctx, w.c, w.link, r)
+}
+
+func (w directWriter) updateRule(r rule) ([]USAReport, error) {
+	return updateRule(w.ctx, w.c, w.link, r)
+}
+
+func (w directWriter) removeRule(kind ruleKind, oid OID) ([]USAReport, error) {
+	return removeRule(w.ctx, w.c, w.link, kind, oid)
+}
+
+// apply creates and updates rules in install order, then removes rules in
+// the reverse order, so that no rule ever refers to a missing one.
+// It stops at the first error.
+func (p rulePlan) apply(w ruleWriter) ([]USAReport, error) {
+	var reports []USAReport
+	for k := ruleBAR; k <= rulePDR; k++ {
+		for _, r := range p.creates {
+			if r.kind != k {
+				continue
+			}
+			err := w.createRule(r)
+			if err != nil {
+				return reports, err
+			}
+		}
+		for _, r := range p.updates {
+			if r.kind != k {
+				continue
+			}
+			rs, err := w.updateRule(r)
+			reports = append(reports, rs...)
+			if err != nil {
+				return reports, err
+			}
+		}
+	}
+	for k := rulePDR; k >= ruleBAR; k-- {
+		for _, r := range p.removes {
+			if r.kind != k {
+				continue
+			}
+			rs, err := w.removeRule(r.kind, r.oid)
+			reports = append(reports, rs...)
+			if err != nil {
+				return reports, err
+			}
+		}
+	}
+	return reports, nil
+}
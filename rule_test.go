@@ -0,0 +1,172 @@
+package gtp5gnl
+
+import (
+	"bytes"
+	"net"
+	"testing"
+
+	"github.com/khirono/go-nl"
+)
+
+func sessionRules(t testing.TB, s *Session) []rule {
+	t.Helper()
+	rs, err := s.rules()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rs
+}
+
+func TestDiffRules(t *testing.T) {
+	farid := uint32(1)
+	farid2 := uint32(2)
+	cur := &Session{
+		SEID: 1,
+		PDRs: []PDR{{ID: 1, FARID: &farid}, {ID: 2, FARID: &farid}},
+		FARs: []FAR{{ID: 1, Action: 2}},
+		QERs: []QER{{ID: 1, QFI: 9}},
+	}
+	next := &Session{
+		SEID: 1,
+		PDRs: []PDR{{ID: 1, FARID: &farid}, {ID: 2, FARID: &farid2}},
+		FARs: []FAR{{ID: 1, Action: 2}, {ID: 2, Action: 1}},
+	}
+	p := diffRules(sessionRules(t, cur), sessionRules(t, next))
+
+	keys := func(rs []rule) []ruleKey {
+		var ks []ruleKey
+		for _, r := range rs {
+			ks = append(ks, r.key())
+		}
+		return ks
+	}
+	check := func(name string, got, want []ruleKey) {
+		t.Helper()
+		if len(got) != len(want) {
+			t.Fatalf("%v: want %v; but got %v\n", name, want, got)
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("%v: want %v; but got %v\n", name, want, got)
+			}
+		}
+	}
+	check("creates", keys(p.creates), []ruleKey{{ruleFAR, 1, 2}})
+	check("updates", keys(p.updates), []ruleKey{{rulePDR, 1, 2}})
+	check("removes", keys(p.removes), []ruleKey{{ruleQER, 1, 1}})
+
+	p = diffRules(sessionRules(t, next), sessionRules(t, next))
+	if !p.empty() {
+		t.Errorf("want empty plan; but got %+v\n", p)
+	}
+}
+
+func TestDiffRulesNormalize(t *testing.T) {
+	// The decoder turns the framed route into its network address, so the
+	// kernel dump of the PDR differs from its raw encoding.
+	want := &Session{
+		SEID: 1,
+		PDRs: []PDR{{
+			ID: 1,
+			PDI: &PDI{
+				FramedRoutes: []net.IPNet{{
+					IP:   net.IPv4(10, 60, 1, 5).To4(),
+					Mask: net.CIDRMask(24, 32),
+				}},
+			},
+		}},
+	}
+	pdr, err := DecodePDR(encodeAttrs(t, encodePDR(t, &want.PDRs[0])))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pdr.ID = 1
+	r, err := pdrRule(1, pdr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cur := []rule{r}
+
+	p := diffRules(cur, sessionRules(t, want))
+	if !p.empty() {
+		t.Errorf("want empty plan; but got %+v\n", p)
+	}
+
+	want.PDRs[0].PDI.FramedRoutes[0].Mask = net.CIDRMask(16, 32)
+	p = diffRules(cur, sessionRules(t, want))
+	if len(p.updates) != 1 {
+		t.Errorf("want 1 update; but got %+v\n", p)
+	}
+}
+
+func TestSessionRulesOrder(t *testing.T) {
+	s := &Session{
+		SEID: 1,
+		PDRs: []PDR{{ID: 1}},
+		FARs: []FAR{{ID: 1}},
+		QERs: []QER{{ID: 1}},
+		URRs: []URR{{ID: 1}},
+		BARs: []BAR{{ID: 1}},
+	}
+	want := []ruleKind{ruleBAR, ruleFAR, ruleQER, ruleURR, rulePDR}
+	rs := sessionRules(t, s)
+	if len(rs) != len(want) {
+		t.Fatalf("want %v rules; but got %v\n", len(want), len(rs))
+	}
+	for i, r := range rs {
+		if r.kind != want[i] {
+			t.Errorf("rule %v: want %v; but got %v\n", i, want[i], r.kind)
+		}
+		if !r.oid.Equal(OID{1, 1}) {
+			t.Errorf("rule %v: want oid %v; but got %v\n", i, OID{1, 1}, r.oid)
+		}
+	}
+}
+
+func TestRawAttrs(t *testing.T) {
+	farid := uint32(1)
+	unknown := []nl.Attr{
+		{
+			Type:  0x7f,
+			Value: nl.AttrU32(0xdeadbeef),
+		},
+		{
+			Type: PDR_PDI,
+			Value: nl.AttrList{
+				{
+					Type:  0x7e,
+					Value: nl.AttrList{{Type: 1, Value: nl.AttrU8(5)}},
+				},
+				{
+					Type:  PDI_UE_ADDR_IPV4,
+					Value: nl.AttrBytes(net.IPv4(60, 60, 0, 1).To4()),
+				},
+			},
+		},
+	}
+	attrs := append(encodePDR(t, &PDR{FARID: &farid}), unknown...)
+	b := encodeAttrs(t, append([]nl.Attr{
+		{Type: PDR_ID, Value: nl.AttrU16(1)},
+		{Type: PDR_SEID, Value: nl.AttrU64(1)},
+	}, attrs...))
+
+	raw, err := rawAttrs("PDR", b, PDR_ID, PDR_SEID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := encodeAttrs(t, raw)
+	want := encodeAttrs(t, attrs)
+	if !bytes.Equal(got, want) {
+		t.Errorf("want % x; but got % x", want, got)
+	}
+	pdr, err := DecodePDR(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pdr.Unknown) != 1 || pdr.PDI == nil || len(pdr.PDI.Unknown) != 1 {
+		t.Errorf("unknown attributes lost: %+v", pdr)
+	}
+	if addsAttrs(raw, attrs) {
+		t.Errorf("want no attributes added to the snapshot")
+	}
+}
@@ -0,0 +1,248 @@
+package gtp5gnl
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+// StatSample is the throughput of a link between two reads of its usage
+// statistic by a StatSampler. Rates are in bit/s and packet/s.
+type StatSample struct {
+	Link     string
+	Time     time.Time     // when the counters were read
+	Interval time.Duration // since the previous read
+	Stats    *UsageStatistic
+	// Reset is set when the counters went back or the link index
+	// changed, i.e. the gtp5g module was reloaded or the link recreated.
+	// The rates are zero then and the next sample starts from Stats.
+	Reset bool
+
+	UlRxBps float64
+	UlTxBps float64
+	DlRxBps float64
+	DlTxBps float64
+	UlRxPps float64
+	UlTxPps float64
+	DlRxPps float64
+	DlTxPps float64
+}
+
+// StatSamplerOptions tunes a StatSampler. The zero value is usable.
+type StatSamplerOptions struct {
+	// Interval between reads; default 1s.
+	Interval time.Duration
+	// OnSample is called with each sample from the goroutine running
+	// Run. If nil, the samples are sent on Samples.
+	OnSample func(StatSample)
+	// OnError is called when the statistic of a link could not be read.
+	// It may be nil.
+	OnError func(link string, err error)
+}
+
+// StatSampler reads the usage statistic of gtp5g links at an interval and
+// turns the cumulative counters into rates.
+type StatSampler struct {
+	opts StatSamplerOptions
+	ch   chan StatSample
+
+	mu    sync.Mutex
+	links map[string]*sampledLink
+
+	// replaced by tests
+	resolve func(name string) (int, error)
+	stat    func(ctx context.Context, link *Link) (*UsageStatistic, error)
+}
+
+type sampledLink struct {
+	index int
+	prev  *UsageStatistic
+	at    time.Time
+}
+
+func NewStatSampler(c *Client, opts *StatSamplerOptions) *StatSampler {
+	s := &StatSampler{
+		ch:    make(chan StatSample),
+		links: make(map[string]*sampledLink),
+	}
+	if opts != nil {
+		s.opts = *opts
+	}
+	if s.opts.Interval <= 0 {
+		s.opts.Interval = time.Second
+	}
+	s.resolve = func(name string) (int, error) {
+		link, err := GetLink(name)
+		if err != nil {
+			return 0, err
+		}
+		return link.Index, nil
+	}
+	s.stat = func(ctx context.Context, link *Link) (*UsageStatistic, error) {
+		return GetUsageStatisticContext(ctx, c, link)
+	}
+	return s
+}
+
+// Samples returns the channel the samples are sent on if OnSample is nil.
+func (s *StatSampler) Samples() <-chan StatSample {
+	return s.ch
+}
+
+// Add starts sampling the link named name. The first sample follows the
+// second read.
+func (s *StatSampler) Add(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.links[name] == nil {
+		s.links[name] = new(sampledLink)
+	}
+}
+
+// Remove stops sampling the link named name.
+func (s *StatSampler) Remove(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.links, name)
+}
+
+// Run reads the links every interval until ctx is done, and returns
+// ctx.Err().
+func (s *StatSampler) Run(ctx context.Context) error {
+	ticker := time.NewTicker(s.opts.Interval)
+	defer ticker.Stop()
+	for {
+		err := s.sample(ctx)
+		if err != nil {
+			return err
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// sample reads every link once and delivers the samples.
+func (s *StatSampler) sample(ctx context.Context) error {
+	s.mu.Lock()
+	names := make([]string, 0, len(s.links))
+	for name := range s.links {
+		names = append(names, name)
+	}
+	s.mu.Unlock()
+	slices.Sort(names)
+
+	for _, name := range names {
+		sample, ok, err := s.read(ctx, name)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			if s.opts.OnError != nil {
+				s.opts.OnError(name, err)
+			}
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if s.opts.OnSample != nil {
+			s.opts.OnSample(sample)
+			continue
+		}
+		select {
+		case s.ch <- sample:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// read reads the statistic of a link and returns its sample, or false if
+// it is the first read or the link was removed meanwhile. The link index
+// is resolved again and the read retried once when it fails, in case the
+// link was recreated.
+func (s *StatSampler) read(ctx context.Context, name string) (StatSample, bool, error) {
+	s.mu.Lock()
+	l := s.links[name]
+	var index int
+	if l != nil {
+		index = l.index
+	}
+	s.mu.Unlock()
+	if l == nil {
+		return StatSample{}, false, nil
+	}
+
+	var err error
+	var stat *UsageStatistic
+	if index != 0 {
+		stat, err = s.stat(ctx, &Link{Name: name, Index: index})
+	}
+	if index == 0 || (err != nil && ctx.Err() == nil) {
+		index, err = s.resolve(name)
+		if err != nil {
+			return StatSample{}, false, err
+		}
+		stat, err = s.stat(ctx, &Link{Name: name, Index: index})
+	}
+	if err != nil {
+		return StatSample{}, false, err
+	}
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.links[name] != l {
+		return StatSample{}, false, nil
+	}
+	prev, at, prevIndex := l.prev, l.at, l.index
+	l.prev, l.at, l.index = stat, now, index
+	if prev == nil {
+		return StatSample{}, false, nil
+	}
+	sample := statSample(prev, stat, now.Sub(at))
+	if prevIndex != index {
+		sample = StatSample{Interval: sample.Interval, Stats: stat, Reset: true}
+	}
+	sample.Link = name
+	sample.Time = now
+	return sample, true, nil
+}
+
+// statSample computes the rates between two reads d apart. Reset is set
+// if a counter went back.
+func statSample(prev, cur *UsageStatistic, d time.Duration) StatSample {
+	sample := StatSample{Interval: d, Stats: cur}
+	sec := d.Seconds()
+	rate := func(prev, cur uint64, scale float64) float64 {
+		if cur < prev {
+			sample.Reset = true
+			return 0
+		}
+		if sec <= 0 {
+			return 0
+		}
+		return float64(cur-prev) * scale / sec
+	}
+	sample.UlRxBps = rate(prev.UlVolRx, cur.UlVolRx, 8)
+	sample.UlTxBps = rate(prev.UlVolTx, cur.UlVolTx, 8)
+	sample.DlRxBps = rate(prev.DlVolRx, cur.DlVolRx, 8)
+	sample.DlTxBps = rate(prev.DlVolTx, cur.DlVolTx, 8)
+	sample.UlRxPps = rate(prev.UlPktRx, cur.UlPktRx, 1)
+	sample.UlTxPps = rate(prev.UlPktTx, cur.UlPktTx, 1)
+	sample.DlRxPps = rate(prev.DlPktRx, cur.DlPktRx, 1)
+	sample.DlTxPps = rate(prev.DlPktTx, cur.DlPktTx, 1)
+	if cur.TotalVolRx < prev.TotalVolRx || cur.TotalVolTx < prev.TotalVolTx ||
+		cur.TotalPktRx < prev.TotalPktRx || cur.TotalPktTx < prev.TotalPktTx {
+		sample.Reset = true
+	}
+	if sample.Reset {
+		return StatSample{Interval: d, Stats: cur, Reset: true}
+	}
+	return sample
+}
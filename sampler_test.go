@@ -0,0 +1,150 @@
+package gtp5gnl
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestStatSample(t *testing.T) {
+	prev := &UsageStatistic{UlVolRx: 1000, DlVolTx: 2000, UlPktRx: 10, DlPktTx: 20}
+	cur := &UsageStatistic{UlVolRx: 2000, DlVolTx: 4000, UlPktRx: 20, DlPktTx: 40}
+	s := statSample(prev, cur, 2*time.Second)
+	if s.Reset {
+		t.Fatal("unexpected Reset")
+	}
+	if s.UlRxBps != 4000 || s.DlTxBps != 8000 || s.UlRxPps != 5 || s.DlTxPps != 10 {
+		t.Errorf("got %+v", s)
+	}
+	if s.UlTxBps != 0 || s.DlRxPps != 0 {
+		t.Errorf("want zero rates for unchanged counters, got %+v", s)
+	}
+
+	s = statSample(cur, prev, time.Second)
+	if !s.Reset || s.UlRxBps != 0 || s.Stats != prev {
+		t.Errorf("want Reset without rates, got %+v", s)
+	}
+	s = statSample(&UsageStatistic{TotalPktRx: 5}, &UsageStatistic{}, time.Second)
+	if !s.Reset {
+		t.Error("want Reset for total counters going back")
+	}
+}
+
+// fakeStats serves increasing counters per link index.
+type fakeStats struct {
+	mu      sync.Mutex
+	indexes map[string]int
+	vols    map[int]uint64
+}
+
+func (f *fakeStats) resolve(name string) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	index, ok := f.indexes[name]
+	if !ok {
+		return 0, errors.New("no such link")
+	}
+	return index, nil
+}
+
+func (f *fakeStats) stat(ctx context.Context, link *Link) (*UsageStatistic, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.indexes[link.Name] != link.Index {
+		return nil, errors.New("no such device")
+	}
+	f.vols[link.Index] += 1000
+	v := f.vols[link.Index]
+	return &UsageStatistic{UlVolRx: v, DlVolTx: 2 * v, UlPktRx: v / 100}, nil
+}
+
+func TestStatSampler(t *testing.T) {
+	f := &fakeStats{
+		indexes: map[string]int{"upfgtp0": 1, "upfgtp1": 2},
+		vols:    make(map[int]uint64),
+	}
+	s := NewStatSampler(nil, &StatSamplerOptions{Interval: 10 * time.Millisecond})
+	s.resolve = f.resolve
+	s.stat = f.stat
+	s.Add("upfgtp0")
+	s.Add("upfgtp1")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.Run(ctx)
+
+	recv := func() StatSample {
+		t.Helper()
+		select {
+		case sample := <-s.Samples():
+			return sample
+		case <-time.After(time.Second):
+			t.Fatal("timeout")
+			return StatSample{}
+		}
+	}
+	seen := make(map[string]bool)
+	for range 2 {
+		sample := recv()
+		seen[sample.Link] = true
+		if sample.Reset || sample.UlRxBps <= 0 || sample.DlTxBps != 2*sample.UlRxBps {
+			t.Errorf("got %+v", sample)
+		}
+	}
+	if len(seen) != 2 {
+		t.Errorf("want samples of 2 links, got %v", seen)
+	}
+
+	// recreate upfgtp1
+	f.mu.Lock()
+	f.indexes["upfgtp1"] = 3
+	f.mu.Unlock()
+	for {
+		sample := recv()
+		if sample.Link != "upfgtp1" {
+			continue
+		}
+		if !sample.Reset {
+			t.Errorf("want Reset, got %+v", sample)
+		}
+		break
+	}
+	for {
+		sample := recv()
+		if sample.Link != "upfgtp1" {
+			continue
+		}
+		if sample.Reset || sample.UlRxBps <= 0 {
+			t.Errorf("want rates after reset, got %+v", sample)
+		}
+		break
+	}
+
+	s.Remove("upfgtp0")
+	s.Remove("upfgtp1")
+	cancel()
+}
+
+func TestStatSamplerError(t *testing.T) {
+	f := &fakeStats{indexes: map[string]int{}, vols: make(map[int]uint64)}
+	errc := make(chan string, 1)
+	s := NewStatSampler(nil, &StatSamplerOptions{
+		OnError: func(link string, err error) { errc <- link },
+	})
+	s.resolve = f.resolve
+	s.stat = f.stat
+	s.Add("upfgtp0")
+	err := s.sample(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case link := <-errc:
+		if link != "upfgtp0" {
+			t.Errorf("want error for upfgtp0, got %v", link)
+		}
+	default:
+		t.Error("OnError not called")
+	}
+}
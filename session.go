@@ -0,0 +1,134 @@
+package gtp5gnl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Session holds all the rules of one PFCP session. The SEID fields of the
+// rules are ignored; every rule is installed under Session.SEID.
+type Session struct {
+	SEID uint64
+	PDRs []PDR
+	FARs []FAR
+	QERs []QER
+	URRs []URR
+	BARs []BAR
+}
+
+// rules returns the encoded rules of s in install order.
+func (s *Session) rules() ([]rule, error) {
+	var rs []rule
+	for i := range s.BARs {
+		rs = append(rs, barRule(s.SEID, &s.BARs[i]))
+	}
+	for i := range s.FARs {
+		rs = append(rs, farRule(s.SEID, &s.FARs[i]))
+	}
+	for i := range s.QERs {
+		rs = append(rs, qerRule(s.SEID, &s.QERs[i]))
+	}
+	for i := range s.URRs {
+		rs = append(rs, urrRule(s.SEID, &s.URRs[i]))
+	}
+	for i := range s.PDRs {
+		r, err := pdrRule(s.SEID, &s.PDRs[i])
+		if err != nil {
+			return nil, err
+		}
+		rs = append(rs, r)
+	}
+	return rs, nil
+}
+
+// Establish installs all rules of s: BARs, FARs, QERs and URRs first, then
+// the PDRs referring to them. On error the rules installed so far are
+// removed again.
+func (s *Session) Establish(c *Client, link *Link) error {
+	return s.EstablishContext(context.Background(), c, link)
+}
+
+func (s *Session) EstablishContext(ctx context.Context, c *Client, link *Link) error {
+	rs, err := s.rules()
+	if err != nil {
+		return err
+	}
+	tx := NewTxContext(ctx, c, link)
+	for _, r := range rs {
+		err := tx.createRule(r)
+		if err != nil {
+			return errors.Join(err, tx.RollbackContext(context.WithoutCancel(ctx)))
+		}
+	}
+	tx.Commit()
+	return nil
+}
+
+// Modify turns the installed session s into next. Rules only in next are
+// created, rules in both whose attributes differ are updated, and rules only
+// in s are removed, in an order that never leaves a PDR referring to a
+// missing rule. On success s is replaced by next; on error the changes made
+// so far are rolled back. The usage reports returned by updated and removed
+// URRs are returned.
+func (s *Session) Modify(c *Client, link *Link, next *Session) ([]USAReport, error) {
+	return s.ModifyContext(context.Background(), c, link, next)
+}
+
+func (s *Session) ModifyContext(ctx context.Context, c *Client, link *Link, next *Session) ([]USAReport, error) {
+	if next.SEID != s.SEID {
+		return nil, fmt.Errorf("session SEID mismatch: %v != %v", next.SEID, s.SEID)
+	}
+	cur, err := s.rules()
+	if err != nil {
+		return nil, err
+	}
+	want, err := next.rules()
+	if err != nil {
+		return nil, err
+	}
+	tx := NewTxContext(ctx, c, link)
+	p := diffRules(cur, want)
+	reports, err := p.apply(tx)
+	if err != nil {
+		return reports, errors.Join(err, tx.RollbackContext(context.WithoutCancel(ctx)))
+	}
+	tx.Commit()
+	*s = *next
+	return reports, nil
+}
+
+// Delete removes all rules of s: PDRs first, then URRs, QERs, FARs and
+// BARs. It keeps going on errors so that as much of the session as possible
+// is removed, and returns them joined.
+func (s *Session) Delete(c *Client, link *Link) ([]USAReport, error) {
+	return s.DeleteContext(context.Background(), c, link)
+}
+
+func (s *Session) DeleteContext(ctx context.Context, c *Client, link *Link) ([]USAReport, error) {
+	var reports []USAReport
+	var errs []error
+	remove := func(kind ruleKind, id uint64) {
+		r, err := removeRule(ctx, c, link, kind, OID{s.SEID, id})
+		reports = append(reports, r...)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for i := range s.PDRs {
+		remove(rulePDR, uint64(s.PDRs[i].ID))
+	}
+	for i := range s.URRs {
+		remove(ruleURR, uint64(s.URRs[i].ID))
+	}
+	for i := range s.QERs {
+		remove(ruleQER, uint64(s.QERs[i].ID))
+	}
+	for i := range s.FARs {
+		remove(ruleFAR, uint64(s.FARs[i].ID))
+	}
+	for i := range s.BARs {
+		remove(ruleBAR, uint64(s.BARs[i].ID))
+	}
+	return reports, errors.Join(errs...)
+}
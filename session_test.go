@@ -0,0 +1,75 @@
+package gtp5gnl
+
+import (
+	"net"
+	"testing"
+)
+
+func TestSession(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping testing in short mode")
+	}
+
+	c, err := Dial()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	link, err := GetLink("upfgtp")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	precedence := uint32(255)
+	farid := uint32(1)
+	s := &Session{
+		SEID: 10,
+		PDRs: []PDR{
+			{
+				ID:         1,
+				Precedence: &precedence,
+				FARID:      &farid,
+				PDI: &PDI{
+					UEAddr: net.IP{60, 60, 0, 1},
+				},
+			},
+		},
+		FARs: []FAR{
+			{
+				ID:     1,
+				Action: 2,
+			},
+		},
+	}
+	err = s.Establish(c, link)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	next := &Session{
+		SEID: s.SEID,
+		PDRs: s.PDRs,
+		FARs: []FAR{
+			{
+				ID:     1,
+				Action: 1,
+			},
+		},
+		QERs: []QER{
+			{
+				ID:  1,
+				QFI: 9,
+			},
+		},
+	}
+	_, err = s.Modify(c, link, next)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = s.Delete(c, link)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
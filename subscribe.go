@@ -0,0 +1,179 @@
+package gtp5gnl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"syscall"
+
+	"github.com/khirono/go-genl"
+	"github.com/khirono/go-nl"
+)
+
+// solNetlink is SOL_NETLINK, which package syscall does not define.
+const solNetlink = 270
+
+var (
+	// ErrNoReportGroup is returned by SubscribeReports when the gtp5g
+	// module registers no multicast group.
+	ErrNoReportGroup = errors.New("gtp5g multicast group not found")
+	// ErrReportsDropped is reported by a subscription when the kernel
+	// dropped pushed reports because the socket buffer was full (ENOBUFS).
+	ErrReportsDropped = errors.New("usage reports dropped")
+)
+
+// SubscribeReports joins the gtp5g multicast group and delivers the usage
+// reports the kernel pushes, e.g. when a volume threshold or quota is
+// reached, until ctx is done or c is closed. The channel is closed then.
+//
+// The channel is also closed on the first error, in particular when the
+// kernel dropped reports because the channel was not drained fast enough.
+// The caller should then read the usage with GetMultiReports and subscribe
+// again. SubscribeReportsErr reports the errors instead.
+func (c *Client) SubscribeReports(ctx context.Context) (<-chan USAReport, error) {
+	return c.SubscribeReportsErr(ctx, nil)
+}
+
+// SubscribeReportsErr is like SubscribeReports but sends errors to errc
+// and keeps the subscription unless reading the socket failed. Dropped
+// reports are sent as an error matching both ErrReportsDropped and
+// syscall.ENOBUFS. A nil errc behaves like SubscribeReports.
+func (c *Client) SubscribeReportsErr(ctx context.Context, errc chan<- error) (<-chan USAReport, error) {
+	family, groups := c.family()
+	if len(groups) <= GENL_MCGRP {
+		return nil, ErrNoReportGroup
+	}
+	conn, err := nl.Open(syscall.NETLINK_GENERIC)
+	if err != nil {
+		return nil, err
+	}
+	group := int(groups[GENL_MCGRP].ID)
+	err = syscall.SetsockoptInt(conn.Fd(), solNetlink, syscall.NETLINK_ADD_MEMBERSHIP, group)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("join multicast group %v: %w", group, err)
+	}
+	// The subscription has its own mux so that a slow reader fills the
+	// socket buffer instead of stalling the requests of c.
+	mux, err := nl.NewMux()
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	s := &reportSub{
+		family: uint16(family),
+		conn:   &watchedConn{Conner: conn, failed: make(chan error, 1)},
+		mux:    mux,
+		msgs:   make(chan *nl.Msg),
+		stop:   make(chan struct{}),
+	}
+	err = mux.PushHandler(s.conn, s)
+	if err != nil {
+		mux.Close()
+		conn.Close()
+		return nil, err
+	}
+	s.wg.Add(1)
+	go func() {
+		mux.Serve()
+		s.wg.Done()
+	}()
+
+	ch := make(chan USAReport)
+	go c.serveReports(ctx, s, ch, errc)
+	return ch, nil
+}
+
+func (c *Client) serveReports(ctx context.Context, s *reportSub, ch chan<- USAReport, errc chan<- error) {
+	defer close(ch)
+	defer s.close()
+	for {
+		var err error
+		fatal := false
+		select {
+		case msg := <-s.msgs:
+			var reports []USAReport
+			reports, err = decodeReportMsg(msg)
+			for _, r := range reports {
+				select {
+				case ch <- r:
+				case <-ctx.Done():
+					return
+				case <-c.done:
+					return
+				}
+			}
+		case err = <-s.conn.failed:
+			err = reportsError(err)
+			fatal = !errors.Is(err, ErrReportsDropped)
+		case <-ctx.Done():
+			return
+		case <-c.done:
+			return
+		}
+		if err == nil {
+			continue
+		}
+		if errc == nil {
+			return
+		}
+		select {
+		case errc <- err:
+		case <-ctx.Done():
+			return
+		case <-c.done:
+			return
+		}
+		if fatal {
+			return
+		}
+	}
+}
+
+// decodeReportMsg decodes the usage reports of a message pushed to the
+// multicast group.
+func decodeReportMsg(msg *nl.Msg) ([]USAReport, error) {
+	if len(msg.Body) < genl.SizeofHeader {
+		return nil, &DecodeError{Path: "UR", Len: len(msg.Body), Want: genl.SizeofHeader}
+	}
+	return DecodeAllUSAReports(msg.Body[genl.SizeofHeader:])
+}
+
+// reportsError marks ENOBUFS, which the kernel returns once after it
+// dropped messages for a full socket, as ErrReportsDropped.
+func reportsError(err error) error {
+	if errors.Is(err, syscall.ENOBUFS) {
+		return fmt.Errorf("%w: %w", ErrReportsDropped, err)
+	}
+	return err
+}
+
+// reportSub passes the messages pushed to the multicast group from its mux
+// to serveReports.
+type reportSub struct {
+	family uint16
+	conn   *watchedConn
+	mux    *nl.Mux
+	msgs   chan *nl.Msg
+	stop   chan struct{}
+	wg     sync.WaitGroup
+}
+
+func (s *reportSub) ServeMsg(msg *nl.Msg) bool {
+	if msg.Header.Type != s.family || msg.Header.Pid != 0 {
+		return false
+	}
+	select {
+	case s.msgs <- msg:
+	case <-s.stop:
+	}
+	return true
+}
+
+func (s *reportSub) close() {
+	close(s.stop)
+	s.mux.Close()
+	s.wg.Wait()
+	s.conn.Close()
+}
@@ -0,0 +1,99 @@
+package gtp5gnl
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"syscall"
+	"testing"
+
+	"github.com/khirono/go-genl"
+	"github.com/khirono/go-nl"
+)
+
+func TestDecodeReportMsg(t *testing.T) {
+	attrs := []nl.Attr{
+		{
+			Type: UR,
+			Value: nl.AttrList{
+				{
+					Type:  UR_URRID,
+					Value: nl.AttrU32(1),
+				},
+				{
+					Type:  UR_SEID,
+					Value: nl.AttrU64(2),
+				},
+			},
+		},
+		{
+			Type: UR,
+			Value: nl.AttrList{
+				{
+					Type:  UR_URRID,
+					Value: nl.AttrU32(3),
+				},
+				{
+					Type:  UR_SEID,
+					Value: nl.AttrU64(2),
+				},
+			},
+		},
+	}
+	body := make([]byte, genl.SizeofHeader)
+	body = append(body, encodeAttrs(t, attrs)...)
+	reports, err := decodeReportMsg(&nl.Msg{Body: body})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []USAReport{{URRID: 1, SEID: 2}, {URRID: 3, SEID: 2}}
+	if !reflect.DeepEqual(reports, want) {
+		t.Errorf("want %+v; but got %+v", want, reports)
+	}
+
+	_, err = decodeReportMsg(&nl.Msg{Body: body[:2]})
+	var derr *DecodeError
+	if !errors.As(err, &derr) {
+		t.Errorf("want *DecodeError, got %v", err)
+	}
+}
+
+func TestReportsError(t *testing.T) {
+	err := reportsError(syscall.ENOBUFS)
+	if !errors.Is(err, ErrReportsDropped) || !errors.Is(err, syscall.ENOBUFS) {
+		t.Errorf("want ErrReportsDropped and ENOBUFS, got %v", err)
+	}
+	err = reportsError(syscall.EBADF)
+	if errors.Is(err, ErrReportsDropped) {
+		t.Errorf("EBADF reported as dropped reports: %v", err)
+	}
+}
+
+func TestSubscribeReportsNoGroup(t *testing.T) {
+	c := &Client{sem: make(chan struct{}, 1), done: make(chan struct{})}
+	_, err := c.SubscribeReports(context.Background())
+	if !errors.Is(err, ErrNoReportGroup) {
+		t.Fatalf("want ErrNoReportGroup, got %v", err)
+	}
+}
+
+func TestSubscribeReports(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping testing in short mode")
+	}
+
+	c, err := Dial()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch, err := c.SubscribeReports(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+	for range ch {
+	}
+}
@@ -6,8 +6,6 @@ import (
 	"fmt"
 	"net"
 	"strconv"
-	"sync"
-	"syscall"
 
 	"github.com/free5gc/go-gtp5gnl"
 	"github.com/khirono/go-nl"
@@ -25,11 +23,12 @@ func ParseFAROptions(args []string) ([]nl.Attr, error) {
 		switch opt {
 		case "--action":
 			// --action <apply-action>
+			// number or names joined by ',': DROP, FORW, BUFF, NOCP, DUPL, ...
 			arg, ok := p.GetToken()
 			if !ok {
 				return attrs, fmt.Errorf("option requires argument %q", opt)
 			}
-			v, err := strconv.ParseUint(arg, 0, 8)
+			v, err := gtp5gnl.ParseApplyAction(arg)
 			if err != nil {
 				return attrs, err
 			}
@@ -96,6 +95,21 @@ func ParseFAROptions(args []string) ([]nl.Attr, error) {
 					},
 				},
 			})
+		case "--pfcpsm-flags":
+			// --pfcpsm-flags <flags>
+			// number or names joined by ',': DROBU, SNDEM, QAURR
+			arg, ok := p.GetToken()
+			if !ok {
+				return attrs, fmt.Errorf("option requires argument %q", opt)
+			}
+			v, err := gtp5gnl.ParsePFCPSMReqFlags(arg)
+			if err != nil {
+				return attrs, err
+			}
+			paramv = append(paramv, nl.Attr{
+				Type:  gtp5gnl.FORWARDING_PARAMETER_PFCPSM_REQ_FLAGS,
+				Value: nl.AttrU8(v),
+			})
 		case "--fwd-policy":
 			// --fwd-policy <mark set in iptable>
 			arg, ok := p.GetToken()
@@ -136,31 +150,11 @@ func CmdAddFAR(args []string) error {
 		return err
 	}
 
-	var wg sync.WaitGroup
-	mux, err := nl.NewMux()
-	if err != nil {
-		return err
-	}
-	defer func() {
-		mux.Close()
-		wg.Wait()
-	}()
-	wg.Add(1)
-	go func() {
-		mux.Serve()
-		wg.Done()
-	}()
-
-	conn, err := nl.Open(syscall.NETLINK_GENERIC)
-	if err != nil {
-		return err
-	}
-	defer conn.Close()
-
-	c, err := gtp5gnl.NewClient(conn, mux)
+	c, err := gtp5gnl.Dial()
 	if err != nil {
 		return err
 	}
+	defer c.Close()
 
 	link, err := gtp5gnl.GetLink(ifname)
 	if err != nil {
@@ -185,31 +179,11 @@ func CmdModFAR(args []string) error {
 		return err
 	}
 
-	var wg sync.WaitGroup
-	mux, err := nl.NewMux()
-	if err != nil {
-		return err
-	}
-	defer func() {
-		mux.Close()
-		wg.Wait()
-	}()
-	wg.Add(1)
-	go func() {
-		mux.Serve()
-		wg.Done()
-	}()
-
-	conn, err := nl.Open(syscall.NETLINK_GENERIC)
-	if err != nil {
-		return err
-	}
-	defer conn.Close()
-
-	c, err := gtp5gnl.NewClient(conn, mux)
+	c, err := gtp5gnl.Dial()
 	if err != nil {
 		return err
 	}
+	defer c.Close()
 
 	link, err := gtp5gnl.GetLink(ifname)
 	if err != nil {
@@ -230,31 +204,11 @@ func CmdDeleteFAR(args []string) error {
 		return err
 	}
 
-	var wg sync.WaitGroup
-	mux, err := nl.NewMux()
-	if err != nil {
-		return err
-	}
-	defer func() {
-		mux.Close()
-		wg.Wait()
-	}()
-	wg.Add(1)
-	go func() {
-		mux.Serve()
-		wg.Done()
-	}()
-
-	conn, err := nl.Open(syscall.NETLINK_GENERIC)
-	if err != nil {
-		return err
-	}
-	defer conn.Close()
-
-	c, err := gtp5gnl.NewClient(conn, mux)
+	c, err := gtp5gnl.Dial()
 	if err != nil {
 		return err
 	}
+	defer c.Close()
 
 	link, err := gtp5gnl.GetLink(ifname)
 	if err != nil {
@@ -275,31 +229,11 @@ func CmdGetFAR(args []string) error {
 		return err
 	}
 
-	var wg sync.WaitGroup
-	mux, err := nl.NewMux()
-	if err != nil {
-		return err
-	}
-	defer func() {
-		mux.Close()
-		wg.Wait()
-	}()
-	wg.Add(1)
-	go func() {
-		mux.Serve()
-		wg.Done()
-	}()
-
-	conn, err := nl.Open(syscall.NETLINK_GENERIC)
-	if err != nil {
-		return err
-	}
-	defer conn.Close()
-
-	c, err := gtp5gnl.NewClient(conn, mux)
+	c, err := gtp5gnl.Dial()
 	if err != nil {
 		return err
 	}
+	defer c.Close()
 
 	link, err := gtp5gnl.GetLink(ifname)
 	if err != nil {
@@ -321,31 +255,11 @@ func CmdGetFAR(args []string) error {
 
 // list far
 func CmdListFAR(args []string) error {
-	var wg sync.WaitGroup
-	mux, err := nl.NewMux()
-	if err != nil {
-		return err
-	}
-	defer func() {
-		mux.Close()
-		wg.Wait()
-	}()
-	wg.Add(1)
-	go func() {
-		mux.Serve()
-		wg.Done()
-	}()
-
-	conn, err := nl.Open(syscall.NETLINK_GENERIC)
-	if err != nil {
-		return err
-	}
-	defer conn.Close()
-
-	c, err := gtp5gnl.NewClient(conn, mux)
+	c, err := gtp5gnl.Dial()
 	if err != nil {
 		return err
 	}
+	defer c.Close()
 
 	fars, err := gtp5gnl.GetFARAll(c)
 	if err != nil {
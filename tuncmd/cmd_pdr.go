@@ -6,8 +6,6 @@ import (
 	"fmt"
 	"net"
 	"strconv"
-	"sync"
-	"syscall"
 
 	"github.com/free5gc/go-gtp5gnl"
 	"github.com/khirono/go-nl"
@@ -17,6 +15,8 @@ func ParsePDROptions(args []string) ([]nl.Attr, error) {
 	var attrs []nl.Attr
 	var sdfv nl.AttrList
 	var pdiv nl.AttrList
+	var epfv nl.AttrList
+	var macv nl.AttrList
 	p := NewCmdParser(args)
 	for {
 		opt, ok := p.GetToken()
@@ -260,11 +260,176 @@ func ParsePDROptions(args []string) ([]nl.Attr, error) {
 				Type:  gtp5gnl.PDR_PDN_TYPE,
 				Value: nl.AttrU8(v),
 			})
+		case "--framed-route":
+			// --framed-route <cidr>
+			arg, ok := p.GetToken()
+			if !ok {
+				return attrs, fmt.Errorf("option requires argument %q", opt)
+			}
+			_, v, err := net.ParseCIDR(arg)
+			if err != nil {
+				return attrs, err
+			}
+			pdiv = append(pdiv, nl.Attr{
+				Type:  gtp5gnl.PDI_FRAMED_ROUTE,
+				Value: nl.AttrString(v.String()),
+			})
+		case "--eth-filter-id":
+			// --eth-filter-id <id>
+			arg, ok := p.GetToken()
+			if !ok {
+				return attrs, fmt.Errorf("option requires argument %q", opt)
+			}
+			v, err := strconv.ParseUint(arg, 0, 32)
+			if err != nil {
+				return attrs, err
+			}
+			epfv = append(epfv, nl.Attr{
+				Type:  gtp5gnl.EPF_FILTER_ETHERNET_FILTER_ID,
+				Value: nl.AttrU32(v),
+			})
+		case "--eth-filter-prop":
+			// --eth-filter-prop <properties>
+			// 1: bidirectional
+			arg, ok := p.GetToken()
+			if !ok {
+				return attrs, fmt.Errorf("option requires argument %q", opt)
+			}
+			v, err := strconv.ParseUint(arg, 0, 8)
+			if err != nil {
+				return attrs, err
+			}
+			epfv = append(epfv, nl.Attr{
+				Type:  gtp5gnl.EPF_FILTER_ETHERNET_FILTER_PROPERTIES,
+				Value: nl.AttrU8(v),
+			})
+		case "--eth-src-mac":
+			// --eth-src-mac <mac-address>
+			arg, ok := p.GetToken()
+			if !ok {
+				return attrs, fmt.Errorf("option requires argument %q", opt)
+			}
+			v, err := gtp5gnl.ParseMACAddr(arg)
+			if err != nil {
+				return attrs, err
+			}
+			macv = append(macv, nl.Attr{
+				Type:  gtp5gnl.MACADDRESS_SRC,
+				Value: nl.AttrBytes(v),
+			})
+		case "--eth-dst-mac":
+			// --eth-dst-mac <mac-address>
+			arg, ok := p.GetToken()
+			if !ok {
+				return attrs, fmt.Errorf("option requires argument %q", opt)
+			}
+			v, err := gtp5gnl.ParseMACAddr(arg)
+			if err != nil {
+				return attrs, err
+			}
+			macv = append(macv, nl.Attr{
+				Type:  gtp5gnl.MACADDRESS_DST,
+				Value: nl.AttrBytes(v),
+			})
+		case "--eth-upper-src-mac":
+			// --eth-upper-src-mac <mac-address>
+			arg, ok := p.GetToken()
+			if !ok {
+				return attrs, fmt.Errorf("option requires argument %q", opt)
+			}
+			v, err := gtp5gnl.ParseMACAddr(arg)
+			if err != nil {
+				return attrs, err
+			}
+			macv = append(macv, nl.Attr{
+				Type:  gtp5gnl.MACADDRESS_UPPER_SRC,
+				Value: nl.AttrBytes(v),
+			})
+		case "--eth-upper-dst-mac":
+			// --eth-upper-dst-mac <mac-address>
+			arg, ok := p.GetToken()
+			if !ok {
+				return attrs, fmt.Errorf("option requires argument %q", opt)
+			}
+			v, err := gtp5gnl.ParseMACAddr(arg)
+			if err != nil {
+				return attrs, err
+			}
+			macv = append(macv, nl.Attr{
+				Type:  gtp5gnl.MACADDRESS_UPPER_DST,
+				Value: nl.AttrBytes(v),
+			})
+		case "--eth-type":
+			// --eth-type <ethertype>
+			arg, ok := p.GetToken()
+			if !ok {
+				return attrs, fmt.Errorf("option requires argument %q", opt)
+			}
+			v, err := strconv.ParseUint(arg, 0, 16)
+			if err != nil {
+				return attrs, err
+			}
+			epfv = append(epfv, nl.Attr{
+				Type:  gtp5gnl.EPF_FILTER_ETHERTYPE,
+				Value: nl.AttrU16(v),
+			})
+		case "--eth-ctag", "--eth-stag":
+			// --eth-ctag <pcp=n,dei=n,vid=n>
+			// --eth-stag <pcp=n,dei=n,vid=n>
+			arg, ok := p.GetToken()
+			if !ok {
+				return attrs, fmt.Errorf("option requires argument %q", opt)
+			}
+			tag, err := ParseVLANTag(arg)
+			if err != nil {
+				return attrs, err
+			}
+			typ := uint16(gtp5gnl.EPF_FILTER_CTAG)
+			if opt == "--eth-stag" {
+				typ = gtp5gnl.EPF_FILTER_STAG
+			}
+			epfv = append(epfv, nl.Attr{
+				Type:  typ,
+				Value: nl.AttrBytes(gtp5gnl.EncodeVLANTag(tag)),
+			})
+		case "--eth-sdf-desp":
+			// --eth-sdf-desp <description-string>
+			arg, ok := p.GetToken()
+			if !ok {
+				return attrs, fmt.Errorf("option requires argument %q", opt)
+			}
+			fd, err := ParseFlowDesc(arg)
+			if err != nil {
+				return attrs, err
+			}
+			epfv = append(epfv, nl.Attr{
+				Type: gtp5gnl.EPF_FILTER_SDF_FILTER,
+				Value: nl.AttrList{
+					{
+						Type:  gtp5gnl.SDF_FILTER_FLOW_DESCRIPTION,
+						Value: fd,
+					},
+				},
+			})
 		default:
 			return attrs, fmt.Errorf("unknown option %q", opt)
 		}
 	}
 
+	if len(macv) != 0 {
+		epfv = append(epfv, nl.Attr{
+			Type:  gtp5gnl.EPF_FILTER_MACADDRESS,
+			Value: macv,
+		})
+	}
+
+	if len(epfv) != 0 {
+		pdiv = append(pdiv, nl.Attr{
+			Type:  gtp5gnl.PDI_ETHERNET_PACKET_FILTER,
+			Value: epfv,
+		})
+	}
+
 	if len(sdfv) != 0 {
 		pdiv = append(pdiv, nl.Attr{
 			Type:  gtp5gnl.PDI_SDF_FILTER,
@@ -297,31 +462,11 @@ func CmdAddPDR(args []string) error {
 		return err
 	}
 
-	var wg sync.WaitGroup
-	mux, err := nl.NewMux()
-	if err != nil {
-		return err
-	}
-	defer func() {
-		mux.Close()
-		wg.Wait()
-	}()
-	wg.Add(1)
-	go func() {
-		mux.Serve()
-		wg.Done()
-	}()
-
-	conn, err := nl.Open(syscall.NETLINK_GENERIC)
-	if err != nil {
-		return err
-	}
-	defer conn.Close()
-
-	c, err := gtp5gnl.NewClient(conn, mux)
+	c, err := gtp5gnl.Dial()
 	if err != nil {
 		return err
 	}
+	defer c.Close()
 
 	link, err := gtp5gnl.GetLink(ifname)
 	if err != nil {
@@ -346,31 +491,11 @@ func CmdModPDR(args []string) error {
 		return err
 	}
 
-	var wg sync.WaitGroup
-	mux, err := nl.NewMux()
-	if err != nil {
-		return err
-	}
-	defer func() {
-		mux.Close()
-		wg.Wait()
-	}()
-	wg.Add(1)
-	go func() {
-		mux.Serve()
-		wg.Done()
-	}()
-
-	conn, err := nl.Open(syscall.NETLINK_GENERIC)
-	if err != nil {
-		return err
-	}
-	defer conn.Close()
-
-	c, err := gtp5gnl.NewClient(conn, mux)
+	c, err := gtp5gnl.Dial()
 	if err != nil {
 		return err
 	}
+	defer c.Close()
 
 	link, err := gtp5gnl.GetLink(ifname)
 	if err != nil {
@@ -391,31 +516,11 @@ func CmdDeletePDR(args []string) error {
 		return err
 	}
 
-	var wg sync.WaitGroup
-	mux, err := nl.NewMux()
-	if err != nil {
-		return err
-	}
-	defer func() {
-		mux.Close()
-		wg.Wait()
-	}()
-	wg.Add(1)
-	go func() {
-		mux.Serve()
-		wg.Done()
-	}()
-
-	conn, err := nl.Open(syscall.NETLINK_GENERIC)
-	if err != nil {
-		return err
-	}
-	defer conn.Close()
-
-	c, err := gtp5gnl.NewClient(conn, mux)
+	c, err := gtp5gnl.Dial()
 	if err != nil {
 		return err
 	}
+	defer c.Close()
 
 	link, err := gtp5gnl.GetLink(ifname)
 	if err != nil {
@@ -436,31 +541,11 @@ func CmdGetPDR(args []string) error {
 		return err
 	}
 
-	var wg sync.WaitGroup
-	mux, err := nl.NewMux()
-	if err != nil {
-		return err
-	}
-	defer func() {
-		mux.Close()
-		wg.Wait()
-	}()
-	wg.Add(1)
-	go func() {
-		mux.Serve()
-		wg.Done()
-	}()
-
-	conn, err := nl.Open(syscall.NETLINK_GENERIC)
-	if err != nil {
-		return err
-	}
-	defer conn.Close()
-
-	c, err := gtp5gnl.NewClient(conn, mux)
+	c, err := gtp5gnl.Dial()
 	if err != nil {
 		return err
 	}
+	defer c.Close()
 
 	link, err := gtp5gnl.GetLink(ifname)
 	if err != nil {
@@ -482,31 +567,11 @@ func CmdGetPDR(args []string) error {
 
 // list pdr
 func CmdListPDR(args []string) error {
-	var wg sync.WaitGroup
-	mux, err := nl.NewMux()
-	if err != nil {
-		return err
-	}
-	defer func() {
-		mux.Close()
-		wg.Wait()
-	}()
-	wg.Add(1)
-	go func() {
-		mux.Serve()
-		wg.Done()
-	}()
-
-	conn, err := nl.Open(syscall.NETLINK_GENERIC)
-	if err != nil {
-		return err
-	}
-	defer conn.Close()
-
-	c, err := gtp5gnl.NewClient(conn, mux)
+	c, err := gtp5gnl.Dial()
 	if err != nil {
 		return err
 	}
+	defer c.Close()
 
 	pdrs, err := gtp5gnl.GetPDRAll(c)
 	if err != nil {
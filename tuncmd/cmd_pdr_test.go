@@ -0,0 +1,108 @@
+package tuncmd
+
+import (
+	"testing"
+
+	"github.com/free5gc/go-gtp5gnl"
+	"github.com/khirono/go-nl"
+)
+
+func TestParsePDROptionsEthernet(t *testing.T) {
+	args := []string{
+		"--eth-filter-id", "3",
+		"--eth-filter-prop", "1",
+		"--eth-src-mac", "00:11:22:33:44:55",
+		"--eth-dst-mac", "66:77:88:99:aa:bb",
+		"--eth-type", "0x8100",
+		"--eth-ctag", "pcp=5,vid=100",
+		"--eth-stag", "dei=1,vid=4095",
+		"--eth-sdf-desp", "permit out ip from any to 10.0.0.1",
+	}
+	attrs, err := ParsePDROptions(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	list := nl.AttrList(attrs)
+	b := make([]byte, list.Len())
+	_, err = list.Encode(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pdr, err := gtp5gnl.DecodePDR(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pdr.PDI == nil || len(pdr.PDI.EPFs) != 1 {
+		t.Fatalf("want one ethernet packet filter; but got %+v", pdr.PDI)
+	}
+	epf := pdr.PDI.EPFs[0]
+	if epf.EthFilterID == nil || *epf.EthFilterID != 3 {
+		t.Errorf("filter id: %v", epf.EthFilterID)
+	}
+	if epf.Properties == nil || *epf.Properties != gtp5gnl.EPF_PROPERTIES_BIDE {
+		t.Errorf("properties: %v", epf.Properties)
+	}
+	if len(epf.MACAddrs) != 1 ||
+		epf.MACAddrs[0].SourceMACAddress != "00:11:22:33:44:55" ||
+		epf.MACAddrs[0].DestinationMACAddress != "66:77:88:99:aa:bb" {
+		t.Errorf("mac addresses: %+v", epf.MACAddrs)
+	}
+	if epf.Ethertype == nil || *epf.Ethertype != 0x8100 {
+		t.Errorf("ethertype: %v", epf.Ethertype)
+	}
+	ctag := gtp5gnl.VLANTag{Flags: gtp5gnl.VLAN_TAG_PCP | gtp5gnl.VLAN_TAG_VID, PCP: 5, VID: 100}
+	if epf.CTAG == nil || *epf.CTAG != ctag {
+		t.Errorf("ctag: want %+v; but got %+v", ctag, epf.CTAG)
+	}
+	stag := gtp5gnl.VLANTag{Flags: gtp5gnl.VLAN_TAG_DEI | gtp5gnl.VLAN_TAG_VID, DEI: 1, VID: 4095}
+	if epf.STAG == nil || *epf.STAG != stag {
+		t.Errorf("stag: want %+v; but got %+v", stag, epf.STAG)
+	}
+	if len(epf.SDFs) != 1 || epf.SDFs[0].FD == nil {
+		t.Errorf("sdf filters: %+v", epf.SDFs)
+	}
+}
+
+func TestParseVLANTagError(t *testing.T) {
+	for _, s := range []string{"", "vid", "vid=4096", "pcp=8", "tpid=1"} {
+		_, err := ParseVLANTag(s)
+		if err == nil {
+			t.Errorf("ParseVLANTag(%q): want error", s)
+		}
+	}
+}
+
+func TestParsePDROptionsFramedRoute(t *testing.T) {
+	args := []string{
+		"--framed-route", "192.168.10.0/24",
+		"--framed-route", "10.1.2.3/16",
+	}
+	attrs, err := ParsePDROptions(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	list := nl.AttrList(attrs)
+	b := make([]byte, list.Len())
+	_, err = list.Encode(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pdr, err := gtp5gnl.DecodePDR(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pdr.PDI == nil || len(pdr.PDI.FramedRoutes) != 2 {
+		t.Fatalf("want two framed routes; but got %+v", pdr.PDI)
+	}
+	want := []string{"192.168.10.0/24", "10.1.0.0/16"}
+	for i, route := range pdr.PDI.FramedRoutes {
+		if route.String() != want[i] {
+			t.Errorf("want %v; but got %v", want[i], route.String())
+		}
+	}
+
+	_, err = ParsePDROptions([]string{"--framed-route", "10.1.2.3"})
+	if err == nil {
+		t.Error("want error for an address without prefix length")
+	}
+}
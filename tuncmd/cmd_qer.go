@@ -5,8 +5,6 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
-	"sync"
-	"syscall"
 
 	"github.com/free5gc/go-gtp5gnl"
 	"github.com/khirono/go-nl"
@@ -53,72 +51,84 @@ func ParseQEROptions(args []string) ([]nl.Attr, error) {
 		case "--gbr-dlow":
 			return attrs, fmt.Errorf("option %q is deprecated", opt)
 		case "--mbr-ul":
+			// --mbr-ul <rate>
+			// kbps, or with a unit: 64kbps, 150Mbps, 1.5Gbps
 			arg, ok := p.GetToken()
 			if !ok {
 				return attrs, fmt.Errorf("option requires argument %q", opt)
 			}
-			v, err := strconv.ParseUint(arg, 0, 40)
+			v, err := gtp5gnl.ParseBitRate(arg)
 			if err != nil {
 				return attrs, err
 			}
+			high, low := v.Split()
 			mbrv = append(mbrv, nl.Attr{
 				Type:  gtp5gnl.QER_MBR_UL_HIGH32,
-				Value: nl.AttrU32(v >> 8),
+				Value: nl.AttrU32(high),
 			})
 			mbrv = append(mbrv, nl.Attr{
 				Type:  gtp5gnl.QER_MBR_UL_LOW8,
-				Value: nl.AttrU8(v),
+				Value: nl.AttrU8(low),
 			})
 		case "--mbr-dl":
+			// --mbr-dl <rate>
+			// kbps, or with a unit: 64kbps, 150Mbps, 1.5Gbps
 			arg, ok := p.GetToken()
 			if !ok {
 				return attrs, fmt.Errorf("option requires argument %q", opt)
 			}
-			v, err := strconv.ParseUint(arg, 0, 40)
+			v, err := gtp5gnl.ParseBitRate(arg)
 			if err != nil {
 				return attrs, err
 			}
+			high, low := v.Split()
 			mbrv = append(mbrv, nl.Attr{
 				Type:  gtp5gnl.QER_MBR_DL_HIGH32,
-				Value: nl.AttrU32(v >> 8),
+				Value: nl.AttrU32(high),
 			})
 			mbrv = append(mbrv, nl.Attr{
 				Type:  gtp5gnl.QER_MBR_DL_LOW8,
-				Value: nl.AttrU8(v),
+				Value: nl.AttrU8(low),
 			})
 		case "--gbr-ul":
+			// --gbr-ul <rate>
+			// kbps, or with a unit: 64kbps, 150Mbps, 1.5Gbps
 			arg, ok := p.GetToken()
 			if !ok {
 				return attrs, fmt.Errorf("option requires argument %q", opt)
 			}
-			v, err := strconv.ParseUint(arg, 0, 40)
+			v, err := gtp5gnl.ParseBitRate(arg)
 			if err != nil {
 				return attrs, err
 			}
+			high, low := v.Split()
 			gbrv = append(gbrv, nl.Attr{
 				Type:  gtp5gnl.QER_GBR_UL_HIGH32,
-				Value: nl.AttrU32(v >> 8),
+				Value: nl.AttrU32(high),
 			})
 			gbrv = append(gbrv, nl.Attr{
 				Type:  gtp5gnl.QER_GBR_UL_LOW8,
-				Value: nl.AttrU8(v),
+				Value: nl.AttrU8(low),
 			})
 		case "--gbr-dl":
+			// --gbr-dl <rate>
+			// kbps, or with a unit: 64kbps, 150Mbps, 1.5Gbps
 			arg, ok := p.GetToken()
 			if !ok {
 				return attrs, fmt.Errorf("option requires argument %q", opt)
 			}
-			v, err := strconv.ParseUint(arg, 0, 40)
+			v, err := gtp5gnl.ParseBitRate(arg)
 			if err != nil {
 				return attrs, err
 			}
+			high, low := v.Split()
 			gbrv = append(gbrv, nl.Attr{
 				Type:  gtp5gnl.QER_GBR_DL_HIGH32,
-				Value: nl.AttrU32(v >> 8),
+				Value: nl.AttrU32(high),
 			})
 			gbrv = append(gbrv, nl.Attr{
 				Type:  gtp5gnl.QER_GBR_DL_LOW8,
-				Value: nl.AttrU8(v),
+				Value: nl.AttrU8(low),
 			})
 		case "--qer-corr-id":
 			arg, ok := p.GetToken()
@@ -210,31 +220,11 @@ func CmdAddQER(args []string) error {
 		return err
 	}
 
-	var wg sync.WaitGroup
-	mux, err := nl.NewMux()
-	if err != nil {
-		return err
-	}
-	defer func() {
-		mux.Close()
-		wg.Wait()
-	}()
-	wg.Add(1)
-	go func() {
-		mux.Serve()
-		wg.Done()
-	}()
-
-	conn, err := nl.Open(syscall.NETLINK_GENERIC)
-	if err != nil {
-		return err
-	}
-	defer conn.Close()
-
-	c, err := gtp5gnl.NewClient(conn, mux)
+	c, err := gtp5gnl.Dial()
 	if err != nil {
 		return err
 	}
+	defer c.Close()
 
 	link, err := gtp5gnl.GetLink(ifname)
 	if err != nil {
@@ -259,31 +249,11 @@ func CmdModQER(args []string) error {
 		return err
 	}
 
-	var wg sync.WaitGroup
-	mux, err := nl.NewMux()
-	if err != nil {
-		return err
-	}
-	defer func() {
-		mux.Close()
-		wg.Wait()
-	}()
-	wg.Add(1)
-	go func() {
-		mux.Serve()
-		wg.Done()
-	}()
-
-	conn, err := nl.Open(syscall.NETLINK_GENERIC)
-	if err != nil {
-		return err
-	}
-	defer conn.Close()
-
-	c, err := gtp5gnl.NewClient(conn, mux)
+	c, err := gtp5gnl.Dial()
 	if err != nil {
 		return err
 	}
+	defer c.Close()
 
 	link, err := gtp5gnl.GetLink(ifname)
 	if err != nil {
@@ -304,31 +274,11 @@ func CmdDeleteQER(args []string) error {
 		return err
 	}
 
-	var wg sync.WaitGroup
-	mux, err := nl.NewMux()
-	if err != nil {
-		return err
-	}
-	defer func() {
-		mux.Close()
-		wg.Wait()
-	}()
-	wg.Add(1)
-	go func() {
-		mux.Serve()
-		wg.Done()
-	}()
-
-	conn, err := nl.Open(syscall.NETLINK_GENERIC)
-	if err != nil {
-		return err
-	}
-	defer conn.Close()
-
-	c, err := gtp5gnl.NewClient(conn, mux)
+	c, err := gtp5gnl.Dial()
 	if err != nil {
 		return err
 	}
+	defer c.Close()
 
 	link, err := gtp5gnl.GetLink(ifname)
 	if err != nil {
@@ -349,31 +299,11 @@ func CmdGetQER(args []string) error {
 		return err
 	}
 
-	var wg sync.WaitGroup
-	mux, err := nl.NewMux()
-	if err != nil {
-		return err
-	}
-	defer func() {
-		mux.Close()
-		wg.Wait()
-	}()
-	wg.Add(1)
-	go func() {
-		mux.Serve()
-		wg.Done()
-	}()
-
-	conn, err := nl.Open(syscall.NETLINK_GENERIC)
-	if err != nil {
-		return err
-	}
-	defer conn.Close()
-
-	c, err := gtp5gnl.NewClient(conn, mux)
+	c, err := gtp5gnl.Dial()
 	if err != nil {
 		return err
 	}
+	defer c.Close()
 
 	link, err := gtp5gnl.GetLink(ifname)
 	if err != nil {
@@ -395,31 +325,11 @@ func CmdGetQER(args []string) error {
 
 // list qer
 func CmdListQER(args []string) error {
-	var wg sync.WaitGroup
-	mux, err := nl.NewMux()
-	if err != nil {
-		return err
-	}
-	defer func() {
-		mux.Close()
-		wg.Wait()
-	}()
-	wg.Add(1)
-	go func() {
-		mux.Serve()
-		wg.Done()
-	}()
-
-	conn, err := nl.Open(syscall.NETLINK_GENERIC)
-	if err != nil {
-		return err
-	}
-	defer conn.Close()
-
-	c, err := gtp5gnl.NewClient(conn, mux)
+	c, err := gtp5gnl.Dial()
 	if err != nil {
 		return err
 	}
+	defer c.Close()
 
 	qers, err := gtp5gnl.GetQERAll(c)
 	if err != nil {
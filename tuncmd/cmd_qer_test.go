@@ -0,0 +1,42 @@
+package tuncmd
+
+import (
+	"testing"
+
+	"github.com/free5gc/go-gtp5gnl"
+	"github.com/khirono/go-nl"
+)
+
+func TestParseQEROptionsBitRate(t *testing.T) {
+	args := []string{
+		"--mbr-ul", "150Mbps",
+		"--mbr-dl", "1.5Gbps",
+		"--gbr-ul", "64kbps",
+		"--gbr-dl", "2000",
+	}
+	attrs, err := ParseQEROptions(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	list := nl.AttrList(attrs)
+	b := make([]byte, list.Len())
+	_, err = list.Encode(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	qer, err := gtp5gnl.DecodeQER(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if qer.MBR.UL_Kbps != 150*gtp5gnl.Mbps || qer.MBR.DL_Kbps != 1500*gtp5gnl.Mbps {
+		t.Errorf("MBR: %v / %v", qer.MBR.UL_Kbps, qer.MBR.DL_Kbps)
+	}
+	if qer.GBR.UL_Kbps != 64*gtp5gnl.Kbps || qer.GBR.DL_Kbps != 2*gtp5gnl.Mbps {
+		t.Errorf("GBR: %v / %v", qer.GBR.UL_Kbps, qer.GBR.DL_Kbps)
+	}
+
+	_, err = ParseQEROptions([]string{"--mbr-ul", "fast"})
+	if err == nil {
+		t.Error("want error for an invalid rate")
+	}
+}
@@ -4,8 +4,6 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"sync"
-	"syscall"
 
 	"github.com/free5gc/go-gtp5gnl"
 	"github.com/khirono/go-nl"
@@ -42,31 +40,11 @@ func CmdAddURR(args []string) error {
 		return err
 	}
 
-	var wg sync.WaitGroup
-	mux, err := nl.NewMux()
-	if err != nil {
-		return err
-	}
-	defer func() {
-		mux.Close()
-		wg.Wait()
-	}()
-	wg.Add(1)
-	go func() {
-		mux.Serve()
-		wg.Done()
-	}()
-
-	conn, err := nl.Open(syscall.NETLINK_GENERIC)
-	if err != nil {
-		return err
-	}
-	defer conn.Close()
-
-	c, err := gtp5gnl.NewClient(conn, mux)
+	c, err := gtp5gnl.Dial()
 	if err != nil {
 		return err
 	}
+	defer c.Close()
 
 	link, err := gtp5gnl.GetLink(ifname)
 	if err != nil {
@@ -91,31 +69,11 @@ func CmdModURR(args []string) error {
 		return err
 	}
 
-	var wg sync.WaitGroup
-	mux, err := nl.NewMux()
-	if err != nil {
-		return err
-	}
-	defer func() {
-		mux.Close()
-		wg.Wait()
-	}()
-	wg.Add(1)
-	go func() {
-		mux.Serve()
-		wg.Done()
-	}()
-
-	conn, err := nl.Open(syscall.NETLINK_GENERIC)
-	if err != nil {
-		return err
-	}
-	defer conn.Close()
-
-	c, err := gtp5gnl.NewClient(conn, mux)
+	c, err := gtp5gnl.Dial()
 	if err != nil {
 		return err
 	}
+	defer c.Close()
 
 	link, err := gtp5gnl.GetLink(ifname)
 	if err != nil {
@@ -140,31 +98,11 @@ func CmdDeleteURR(args []string) error {
 		return err
 	}
 
-	var wg sync.WaitGroup
-	mux, err := nl.NewMux()
-	if err != nil {
-		return err
-	}
-	defer func() {
-		mux.Close()
-		wg.Wait()
-	}()
-	wg.Add(1)
-	go func() {
-		mux.Serve()
-		wg.Done()
-	}()
-
-	conn, err := nl.Open(syscall.NETLINK_GENERIC)
-	if err != nil {
-		return err
-	}
-	defer conn.Close()
-
-	c, err := gtp5gnl.NewClient(conn, mux)
+	c, err := gtp5gnl.Dial()
 	if err != nil {
 		return err
 	}
+	defer c.Close()
 
 	link, err := gtp5gnl.GetLink(ifname)
 	if err != nil {
@@ -189,31 +127,11 @@ func CmdGetURR(args []string) error {
 		return err
 	}
 
-	var wg sync.WaitGroup
-	mux, err := nl.NewMux()
-	if err != nil {
-		return err
-	}
-	defer func() {
-		mux.Close()
-		wg.Wait()
-	}()
-	wg.Add(1)
-	go func() {
-		mux.Serve()
-		wg.Done()
-	}()
-
-	conn, err := nl.Open(syscall.NETLINK_GENERIC)
-	if err != nil {
-		return err
-	}
-	defer conn.Close()
-
-	c, err := gtp5gnl.NewClient(conn, mux)
+	c, err := gtp5gnl.Dial()
 	if err != nil {
 		return err
 	}
+	defer c.Close()
 
 	link, err := gtp5gnl.GetLink(ifname)
 	if err != nil {
@@ -235,31 +153,11 @@ func CmdGetURR(args []string) error {
 
 // list urr
 func CmdListURR(args []string) error {
-	var wg sync.WaitGroup
-	mux, err := nl.NewMux()
-	if err != nil {
-		return err
-	}
-	defer func() {
-		mux.Close()
-		wg.Wait()
-	}()
-	wg.Add(1)
-	go func() {
-		mux.Serve()
-		wg.Done()
-	}()
-
-	conn, err := nl.Open(syscall.NETLINK_GENERIC)
-	if err != nil {
-		return err
-	}
-	defer conn.Close()
-
-	c, err := gtp5gnl.NewClient(conn, mux)
+	c, err := gtp5gnl.Dial()
 	if err != nil {
 		return err
 	}
+	defer c.Close()
 
 	urrs, err := gtp5gnl.GetURRAll(c)
 	if err != nil {
@@ -94,10 +94,14 @@ func ParseFlowDesc(s string) (nl.AttrList, error) {
 	if pos >= len(token) {
 		return nil, fmt.Errorf("too few fields %v", len(token))
 	}
-	src, err := ParseFlowDescIPNet(token[pos])
+	srcAddr := token[pos]
+	src, err := ParseFlowDescIPNet(srcAddr)
 	if err != nil {
 		return nil, err
 	}
+	if isIPv6Addr(srcAddr, src) {
+		return nil, fmt.Errorf("IPv6 address %v is not supported", srcAddr)
+	}
 	pos++
 	attrs = append(attrs, nl.Attr{
 		Type:  gtp5gnl.FLOW_DESCRIPTION_SRC_IPV4,
@@ -132,10 +136,14 @@ func ParseFlowDesc(s string) (nl.AttrList, error) {
 	if pos >= len(token) {
 		return nil, fmt.Errorf("too few fields %v", len(token))
 	}
-	dst, err := ParseFlowDescIPNet(token[pos])
+	dstAddr := token[pos]
+	dst, err := ParseFlowDescIPNet(dstAddr)
 	if err != nil {
 		return nil, err
 	}
+	if isIPv6Addr(dstAddr, dst) {
+		return nil, fmt.Errorf("IPv6 address %v is not supported", dstAddr)
+	}
 	pos++
 	attrs = append(attrs, nl.Attr{
 		Type:  gtp5gnl.FLOW_DESCRIPTION_DEST_IPV4,
@@ -186,6 +194,12 @@ func ParseFlowDescIPNet(s string) (*net.IPNet, error) {
 	}, nil
 }
 
+// isIPv6Addr reports whether the flow description address s, parsed as
+// ipnet, is an IPv6 one. "any" is not.
+func isIPv6Addr(s string, ipnet *net.IPNet) bool {
+	return s != "any" && ipnet.IP.To4() == nil
+}
+
 func ParseFlowDescPorts(s string) ([][]uint16, error) {
 	var vals [][]uint16
 	for _, port := range strings.Split(s, ",") {
@@ -0,0 +1,85 @@
+package tuncmd
+
+import (
+	"net"
+	"testing"
+
+	"github.com/free5gc/go-gtp5gnl"
+	"github.com/khirono/go-nl"
+)
+
+func TestParseFlowDescAddr(t *testing.T) {
+	cases := []struct {
+		name    string
+		s       string
+		srcType uint16
+		dstType uint16
+		src     net.IP
+		dst     net.IP
+		wantErr bool
+	}{
+		{
+			name:    "ipv4",
+			s:       "permit out ip from 10.0.0.0/8 to 60.60.0.1",
+			srcType: gtp5gnl.FLOW_DESCRIPTION_SRC_IPV4,
+			dstType: gtp5gnl.FLOW_DESCRIPTION_DEST_IPV4,
+			src:     net.IP{10, 0, 0, 0},
+			dst:     net.IP{60, 60, 0, 1},
+		},
+		{
+			name:    "any to ipv4",
+			s:       "permit out 17 from any to 60.60.0.0/16 53",
+			srcType: gtp5gnl.FLOW_DESCRIPTION_SRC_IPV4,
+			dstType: gtp5gnl.FLOW_DESCRIPTION_DEST_IPV4,
+			src:     net.IPv6zero,
+			dst:     net.IP{60, 60, 0, 0},
+		},
+		{
+			name:    "ipv6",
+			s:       "permit out 17 from 2001:db8:2::/48 53 to 2001:db8:1::1",
+			wantErr: true,
+		},
+		{
+			name:    "any to ipv6",
+			s:       "permit out ip from any to 2001:db8:1::/64",
+			wantErr: true,
+		},
+		{
+			name:    "mixed",
+			s:       "permit out ip from 10.0.0.1 to 2001:db8:1::1",
+			wantErr: true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			attrs, err := ParseFlowDesc(tc.s)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatal("want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			src := findAttr(attrs, tc.srcType)
+			if src == nil || !net.IP(src).Equal(tc.src) {
+				t.Errorf("source address: want %v; but got %v", tc.src, net.IP(src))
+			}
+			dst := findAttr(attrs, tc.dstType)
+			if dst == nil || !net.IP(dst).Equal(tc.dst) {
+				t.Errorf("destination address: want %v; but got %v", tc.dst, net.IP(dst))
+			}
+		})
+	}
+}
+
+func findAttr(attrs nl.AttrList, typ uint16) []byte {
+	for _, attr := range attrs {
+		if attr.Type == typ {
+			b, _ := attr.Value.(nl.AttrBytes)
+			return b
+		}
+	}
+	return nil
+}
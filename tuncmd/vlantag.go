@@ -0,0 +1,47 @@
+package tuncmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/free5gc/go-gtp5gnl"
+)
+
+// VLANTag <- field (',' field)*
+// field <- ('pcp' / 'dei' / 'vid') '=' digit
+func ParseVLANTag(s string) (gtp5gnl.VLANTag, error) {
+	var tag gtp5gnl.VLANTag
+	for _, field := range strings.Split(s, ",") {
+		kv := strings.SplitN(field, "=", 2)
+		if len(kv) != 2 {
+			return tag, fmt.Errorf("invalid VLAN tag field %q", field)
+		}
+		switch kv[0] {
+		case "pcp":
+			v, err := strconv.ParseUint(kv[1], 0, 3)
+			if err != nil {
+				return tag, err
+			}
+			tag.PCP = uint8(v)
+			tag.Flags |= gtp5gnl.VLAN_TAG_PCP
+		case "dei":
+			v, err := strconv.ParseUint(kv[1], 0, 1)
+			if err != nil {
+				return tag, err
+			}
+			tag.DEI = uint8(v)
+			tag.Flags |= gtp5gnl.VLAN_TAG_DEI
+		case "vid":
+			v, err := strconv.ParseUint(kv[1], 0, 12)
+			if err != nil {
+				return tag, err
+			}
+			tag.VID = uint16(v)
+			tag.Flags |= gtp5gnl.VLAN_TAG_VID
+		default:
+			return tag, fmt.Errorf("unknown VLAN tag field %q", kv[0])
+		}
+	}
+	return tag, nil
+}
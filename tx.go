@@ -0,0 +1,238 @@
+package gtp5gnl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/khirono/go-nl"
+)
+
+// Tx records the rule changes made through it so that they can be undone
+// with Rollback. Updates and removals snapshot the current rule first, as
+// the kernel sends it, so that Rollback can restore it with the attributes
+// this package does not model.
+//
+// An update is undone by sending the snapshot again. The kernel keeps the
+// attributes an update adds, so a PDR whose update added any is removed and
+// created again from the snapshot instead. Other rules are not: a URR
+// created again would lose its usage counters, and a FAR, QER or BAR is
+// referred to by PDRs. Rollback returns ErrAttrsKept for them.
+type Tx struct {
+	ctx  context.Context
+	c    *Client
+	link *Link
+	undo []txUndo
+}
+
+// ErrAttrsKept is returned by Rollback for a FAR, QER, URR or BAR restored
+// from its snapshot after an update that added attributes, which the kernel
+// keeps.
+var ErrAttrsKept = errors.New("attributes added by the update are kept")
+
+type txUndo struct {
+	desc string
+	do   func(ctx context.Context) error
+}
+
+func NewTx(c *Client, link *Link) *Tx {
+	return NewTxContext(context.Background(), c, link)
+}
+
+// NewTxContext returns a Tx whose requests, including those of Rollback,
+// are bound to ctx.
+func NewTxContext(ctx context.Context, c *Client, link *Link) *Tx {
+	return &Tx{ctx: ctx, c: c, link: link}
+}
+
+// Commit forgets the recorded changes; a later Rollback does nothing.
+func (tx *Tx) Commit() {
+	tx.undo = nil
+}
+
+// Rollback undoes the recorded changes in reverse order. It keeps going on
+// errors and returns them joined.
+func (tx *Tx) Rollback() error {
+	return tx.RollbackContext(tx.ctx)
+}
+
+// RollbackContext is like Rollback but binds the undo requests to ctx,
+// e.g. when the context of tx has already expired.
+func (tx *Tx) RollbackContext(ctx context.Context) error {
+	var errs []error
+	for i := len(tx.undo) - 1; i >= 0; i-- {
+		u := tx.undo[i]
+		err := u.do(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("rollback %v: %w", u.desc, err))
+		}
+	}
+	tx.undo = nil
+	return errors.Join(errs...)
+}
+
+func (tx *Tx) createRule(r rule) error {
+	err := createRule(tx.ctx, tx.c, tx.link, r)
+	if err != nil {
+		return err
+	}
+	tx.undo = append(tx.undo, txUndo{
+		desc: fmt.Sprintf("create %v oid(%v)", r.kind, r.oid),
+		do: func(ctx context.Context) error {
+			_, err := removeRule(ctx, tx.c, tx.link, r.kind, r.oid)
+			return err
+		},
+	})
+	return nil
+}
+
+func (tx *Tx) updateRule(r rule) ([]USAReport, error) {
+	prev, err := getRule(tx.ctx, tx.c, tx.link, r.kind, r.oid)
+	if err != nil {
+		return nil, err
+	}
+	reports, err := updateRule(tx.ctx, tx.c, tx.link, r)
+	if err != nil {
+		return reports, err
+	}
+	added := addsAttrs(prev.attrs, r.attrs)
+	tx.undo = append(tx.undo, txUndo{
+		desc: fmt.Sprintf("update %v oid(%v)", r.kind, r.oid),
+		do: func(ctx context.Context) error {
+			if added && prev.kind == rulePDR {
+				_, err := removeRule(ctx, tx.c, tx.link, prev.kind, prev.oid)
+				if err != nil {
+					return err
+				}
+				return createRule(ctx, tx.c, tx.link, prev)
+			}
+			_, err := updateRule(ctx, tx.c, tx.link, prev)
+			if err != nil {
+				return err
+			}
+			if added {
+				return ErrAttrsKept
+			}
+			return nil
+		},
+	})
+	return reports, nil
+}
+
+// addsAttrs reports whether attrs sets an attribute, possibly nested, that
+// base does not. The attributes of base may be raw, as read by getRule.
+func addsAttrs(base, attrs []nl.Attr) bool {
+	for _, a := range attrs {
+		i := slices.IndexFunc(base, func(b nl.Attr) bool {
+			return b.Type&nl.NLA_TYPE_MASK == a.Type&nl.NLA_TYPE_MASK
+		})
+		if i < 0 {
+			return true
+		}
+		if l := attrList(a.Value); l != nil && addsAttrs(nestedAttrs(base[i].Value), l) {
+			return true
+		}
+	}
+	return false
+}
+
+// nestedAttrs returns the attributes nested in v, splitting v if it is a
+// raw payload.
+func nestedAttrs(v nl.Encoder) []nl.Attr {
+	b, ok := v.(nl.AttrBytes)
+	if !ok {
+		return attrList(v)
+	}
+	attrs, err := rawAttrs("", b)
+	if err != nil {
+		return nil
+	}
+	return attrs
+}
+
+func (tx *Tx) removeRule(kind ruleKind, oid OID) ([]USAReport, error) {
+	prev, err := getRule(tx.ctx, tx.c, tx.link, kind, oid)
+	if err != nil {
+		return nil, err
+	}
+	reports, err := removeRule(tx.ctx, tx.c, tx.link, kind, oid)
+	if err != nil {
+		return reports, err
+	}
+	tx.undo = append(tx.undo, txUndo{
+		desc: fmt.Sprintf("remove %v oid(%v)", kind, oid),
+		do: func(ctx context.Context) error {
+			return createRule(ctx, tx.c, tx.link, prev)
+		},
+	})
+	return reports, nil
+}
+
+func (tx *Tx) CreatePDR(oid OID, attrs []nl.Attr) error {
+	return tx.createRule(rule{kind: rulePDR, oid: oid, attrs: attrs})
+}
+
+func (tx *Tx) UpdatePDR(oid OID, attrs []nl.Attr) error {
+	_, err := tx.updateRule(rule{kind: rulePDR, oid: oid, attrs: attrs})
+	return err
+}
+
+func (tx *Tx) RemovePDR(oid OID) error {
+	_, err := tx.removeRule(rulePDR, oid)
+	return err
+}
+
+func (tx *Tx) CreateFAR(oid OID, attrs []nl.Attr) error {
+	return tx.createRule(rule{kind: ruleFAR, oid: oid, attrs: attrs})
+}
+
+func (tx *Tx) UpdateFAR(oid OID, attrs []nl.Attr) error {
+	_, err := tx.updateRule(rule{kind: ruleFAR, oid: oid, attrs: attrs})
+	return err
+}
+
+func (tx *Tx) RemoveFAR(oid OID) error {
+	_, err := tx.removeRule(ruleFAR, oid)
+	return err
+}
+
+func (tx *Tx) CreateQER(oid OID, attrs []nl.Attr) error {
+	return tx.createRule(rule{kind: ruleQER, oid: oid, attrs: attrs})
+}
+
+func (tx *Tx) UpdateQER(oid OID, attrs []nl.Attr) error {
+	_, err := tx.updateRule(rule{kind: ruleQER, oid: oid, attrs: attrs})
+	return err
+}
+
+func (tx *Tx) RemoveQER(oid OID) error {
+	_, err := tx.removeRule(ruleQER, oid)
+	return err
+}
+
+func (tx *Tx) CreateURR(oid OID, attrs []nl.Attr) error {
+	return tx.createRule(rule{kind: ruleURR, oid: oid, attrs: attrs})
+}
+
+func (tx *Tx) UpdateURR(oid OID, attrs []nl.Attr) ([]USAReport, error) {
+	return tx.updateRule(rule{kind: ruleURR, oid: oid, attrs: attrs})
+}
+
+func (tx *Tx) RemoveURR(oid OID) ([]USAReport, error) {
+	return tx.removeRule(ruleURR, oid)
+}
+
+func (tx *Tx) CreateBAR(oid OID, attrs []nl.Attr) error {
+	return tx.createRule(rule{kind: ruleBAR, oid: oid, attrs: attrs})
+}
+
+func (tx *Tx) UpdateBAR(oid OID, attrs []nl.Attr) error {
+	_, err := tx.updateRule(rule{kind: ruleBAR, oid: oid, attrs: attrs})
+	return err
+}
+
+func (tx *Tx) RemoveBAR(oid OID) error {
+	_, err := tx.removeRule(ruleBAR, oid)
+	return err
+}
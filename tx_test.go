@@ -0,0 +1,134 @@
+package gtp5gnl
+
+import (
+	"net"
+	"testing"
+)
+
+func TestTxRollback(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping testing in short mode")
+	}
+
+	c, err := Dial()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	link, err := GetLink("upfgtp")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	farid := uint32(1)
+	tx := NewTx(c, link)
+	err = tx.CreateFAR(OID{20, 1}, EncodeFAR(&FAR{Action: 2}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = tx.CreatePDR(OID{20, 1}, encodePDR(t, &PDR{FARID: &farid}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = tx.CreatePDR(OID{20, 1}, encodePDR(t, &PDR{FARID: &farid}))
+	if err == nil {
+		t.Fatal("want error for duplicated PDR")
+	}
+	err = tx.Rollback()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = GetFAROID(c, link, OID{20, 1})
+	if err == nil {
+		t.Errorf("want FAR removed by rollback")
+	}
+}
+
+func TestAddsAttrs(t *testing.T) {
+	farid := uint32(1)
+	qerid := uint32(2)
+	srcIntf := uint8(1)
+	teid := &FTEID{TEID: 1, GTPuAddr: net.IP{10, 0, 0, 1}}
+	base := encodePDR(t, &PDR{FARID: &farid, PDI: &PDI{SrcIntf: &srcIntf}})
+	raw, err := rawAttrs("PDR", encodeAttrs(t, base))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name string
+		pdr  PDR
+		want bool
+	}{
+		{"same", PDR{FARID: &farid, PDI: &PDI{SrcIntf: &srcIntf}}, false},
+		{"fewer", PDR{FARID: &farid}, false},
+		{"qer id", PDR{FARID: &farid, QERID: []uint32{qerid}, PDI: &PDI{SrcIntf: &srcIntf}}, true},
+		{"nested", PDR{FARID: &farid, PDI: &PDI{SrcIntf: &srcIntf, FTEID: teid}}, true},
+	}
+	for _, tc := range cases {
+		got := addsAttrs(base, encodePDR(t, &tc.pdr))
+		if got != tc.want {
+			t.Errorf("%v: want %v, got %v", tc.name, tc.want, got)
+		}
+		got = addsAttrs(raw, encodePDR(t, &tc.pdr))
+		if got != tc.want {
+			t.Errorf("%v (raw): want %v, got %v", tc.name, tc.want, got)
+		}
+	}
+}
+
+func TestTxRollbackUpdate(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping testing in short mode")
+	}
+
+	c, err := Dial()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	link, err := GetLink("upfgtp")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	farid := uint32(1)
+	qerid := uint32(1)
+	oid := OID{21, 1}
+	err = CreateFAROID(c, link, oid, EncodeFAR(&FAR{Action: 2}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer RemoveFAROID(c, link, oid)
+	err = CreateQEROID(c, link, oid, EncodeQER(&QER{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer RemoveQEROID(c, link, oid)
+	err = CreatePDROID(c, link, oid, encodePDR(t, &PDR{FARID: &farid}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer RemovePDROID(c, link, oid)
+
+	tx := NewTx(c, link)
+	err = tx.UpdatePDR(oid, encodePDR(t, &PDR{FARID: &farid, QERID: []uint32{qerid}}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = tx.Rollback()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pdr, err := GetPDROID(c, link, oid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pdr.QERID) != 0 {
+		t.Errorf("want QER ID removed by rollback, got %v", pdr.QERID)
+	}
+}
@@ -1,6 +1,7 @@
 package gtp5gnl
 
 import (
+	"context"
 	"fmt"
 	"syscall"
 
@@ -21,9 +22,13 @@ func CreateURR(c *Client, link *Link, urrid int, attrs []nl.Attr) error {
 }
 
 func CreateURROID(c *Client, link *Link, oid OID, attrs []nl.Attr) error {
+	return CreateURROIDContext(context.Background(), c, link, oid, attrs)
+}
+
+func CreateURROIDContext(ctx context.Context, c *Client, link *Link, oid OID, attrs []nl.Attr) error {
 	flags := syscall.NLM_F_EXCL
 	flags |= syscall.NLM_F_ACK
-	req := nl.NewRequest(c.ID, flags)
+	req := nl.NewRequest(c.familyID(), flags)
 	err := req.Append(genl.Header{Cmd: CMD_ADD_URR})
 	if err != nil {
 		return err
@@ -59,8 +64,8 @@ func CreateURROID(c *Client, link *Link, oid OID, attrs []nl.Attr) error {
 	if err != nil {
 		return err
 	}
-	_, err = c.Do(req)
-	return err
+	_, err = c.DoContext(ctx, req)
+	return withOID(err, oid)
 }
 
 func UpdateURR(c *Client, link *Link, urrid int, attrs []nl.Attr) ([]USAReport, error) {
@@ -68,9 +73,13 @@ func UpdateURR(c *Client, link *Link, urrid int, attrs []nl.Attr) ([]USAReport,
 }
 
 func UpdateURROID(c *Client, link *Link, oid OID, attrs []nl.Attr) ([]USAReport, error) {
+	return UpdateURROIDContext(context.Background(), c, link, oid, attrs)
+}
+
+func UpdateURROIDContext(ctx context.Context, c *Client, link *Link, oid OID, attrs []nl.Attr) ([]USAReport, error) {
 	flags := syscall.NLM_F_REPLACE
 	flags |= syscall.NLM_F_ACK
-	req := nl.NewRequest(c.ID, flags)
+	req := nl.NewRequest(c.familyID(), flags)
 	err := req.Append(genl.Header{Cmd: CMD_ADD_URR})
 	if err != nil {
 		return nil, err
@@ -106,9 +115,9 @@ func UpdateURROID(c *Client, link *Link, oid OID, attrs []nl.Attr) ([]USAReport,
 	if err != nil {
 		return nil, err
 	}
-	rsps, err := c.Do(req)
+	rsps, err := c.DoContext(ctx, req)
 	if err != nil {
-		return nil, err
+		return nil, withOID(err, oid)
 	}
 	if len(rsps) < 1 {
 		return nil, nil
@@ -125,9 +134,13 @@ func RemoveURR(c *Client, link *Link, urrid int) ([]USAReport, error) {
 }
 
 func RemoveURROID(c *Client, link *Link, oid OID) ([]USAReport, error) {
+	return RemoveURROIDContext(context.Background(), c, link, oid)
+}
+
+func RemoveURROIDContext(ctx context.Context, c *Client, link *Link, oid OID) ([]USAReport, error) {
 	flags := syscall.NLM_F_EXCL
 	flags |= syscall.NLM_F_ACK
-	req := nl.NewRequest(c.ID, flags)
+	req := nl.NewRequest(c.familyID(), flags)
 	err := req.Append(genl.Header{Cmd: CMD_DEL_URR})
 	if err != nil {
 		return nil, err
@@ -159,9 +172,9 @@ func RemoveURROID(c *Client, link *Link, oid OID) ([]USAReport, error) {
 			return nil, err
 		}
 	}
-	rsps, err := c.Do(req)
+	rsps, err := c.DoContext(ctx, req)
 	if err != nil {
-		return nil, err
+		return nil, withOID(err, oid)
 	}
 	if len(rsps) < 1 {
 		return nil, fmt.Errorf("RemoveURROID(%v): no usage report", oid)
@@ -178,8 +191,12 @@ func GetURR(c *Client, link *Link, urrid int) (*URR, error) {
 }
 
 func GetURROID(c *Client, link *Link, oid OID) (*URR, error) {
+	return GetURROIDContext(context.Background(), c, link, oid)
+}
+
+func GetURROIDContext(ctx context.Context, c *Client, link *Link, oid OID) (*URR, error) {
 	flags := syscall.NLM_F_ACK
-	req := nl.NewRequest(c.ID, flags)
+	req := nl.NewRequest(c.familyID(), flags)
 	err := req.Append(genl.Header{Cmd: CMD_GET_URR})
 	if err != nil {
 		return nil, err
@@ -211,12 +228,12 @@ func GetURROID(c *Client, link *Link, oid OID) (*URR, error) {
 			return nil, err
 		}
 	}
-	rsps, err := c.Do(req)
+	rsps, err := c.DoContext(ctx, req)
 	if err != nil {
-		return nil, err
+		return nil, withOID(err, oid)
 	}
 	if len(rsps) < 1 {
-		return nil, fmt.Errorf("GetURROID: nil URR of oid(%v)", oid)
+		return nil, notFound(CMD_GET_URR, oid)
 	}
 	urr, err := DecodeURR(rsps[0].Body[genl.SizeofHeader:])
 	if err != nil {
@@ -226,13 +243,17 @@ func GetURROID(c *Client, link *Link, oid OID) (*URR, error) {
 }
 
 func GetURRAll(c *Client) ([]URR, error) {
+	return GetURRAllContext(context.Background(), c)
+}
+
+func GetURRAllContext(ctx context.Context, c *Client) ([]URR, error) {
 	flags := syscall.NLM_F_DUMP
-	req := nl.NewRequest(c.ID, flags)
+	req := nl.NewRequest(c.familyID(), flags)
 	err := req.Append(genl.Header{Cmd: CMD_GET_URR})
 	if err != nil {
 		return nil, err
 	}
-	rsps, err := c.Do(req)
+	rsps, err := c.DoContext(ctx, req)
 	if err != nil {
 		return nil, err
 	}
@@ -3,8 +3,6 @@ package gtp5gnl
 import (
 	"encoding/json"
 	"log"
-	"sync"
-	"syscall"
 	"testing"
 
 	"github.com/khirono/go-nl"
@@ -15,31 +13,11 @@ func TestCreateURR(t *testing.T) {
 		t.Skip("skipping testing in short mode")
 	}
 
-	var wg sync.WaitGroup
-	mux, err := nl.NewMux()
-	if err != nil {
-		t.Fatal(err)
-	}
-	defer func() {
-		mux.Close()
-		wg.Wait()
-	}()
-	wg.Add(1)
-	go func() {
-		mux.Serve()
-		wg.Done()
-	}()
-
-	conn, err := nl.Open(syscall.NETLINK_GENERIC)
-	if err != nil {
-		t.Fatal(err)
-	}
-	defer conn.Close()
-
-	c, err := NewClient(conn, mux)
+	c, err := Dial()
 	if err != nil {
 		t.Fatal(err)
 	}
+	defer c.Close()
 
 	link, err := GetLink("upfgtp")
 	if err != nil {
@@ -118,31 +96,11 @@ func TestGetURR(t *testing.T) {
 		t.Skip("skipping testing in short mode")
 	}
 
-	var wg sync.WaitGroup
-	mux, err := nl.NewMux()
-	if err != nil {
-		t.Fatal(err)
-	}
-	defer func() {
-		mux.Close()
-		wg.Wait()
-	}()
-	wg.Add(1)
-	go func() {
-		mux.Serve()
-		wg.Done()
-	}()
-
-	conn, err := nl.Open(syscall.NETLINK_GENERIC)
-	if err != nil {
-		t.Fatal(err)
-	}
-	defer conn.Close()
-
-	c, err := NewClient(conn, mux)
+	c, err := Dial()
 	if err != nil {
 		t.Fatal(err)
 	}
+	defer c.Close()
 
 	link, err := GetLink("upfgtp")
 	if err != nil {
@@ -166,31 +124,11 @@ func TestGetURRAll(t *testing.T) {
 		t.Skip("skipping testing in short mode")
 	}
 
-	var wg sync.WaitGroup
-	mux, err := nl.NewMux()
-	if err != nil {
-		t.Fatal(err)
-	}
-	defer func() {
-		mux.Close()
-		wg.Wait()
-	}()
-	wg.Add(1)
-	go func() {
-		mux.Serve()
-		wg.Done()
-	}()
-
-	conn, err := nl.Open(syscall.NETLINK_GENERIC)
-	if err != nil {
-		t.Fatal(err)
-	}
-	defer conn.Close()
-
-	c, err := NewClient(conn, mux)
+	c, err := Dial()
 	if err != nil {
 		t.Fatal(err)
 	}
+	defer c.Close()
 
 	urrs, err := GetURRAll(c)
 	if err != nil {
@@ -209,31 +147,11 @@ func TestRemoveURR(t *testing.T) {
 		t.Skip("skipping testing in short mode")
 	}
 
-	var wg sync.WaitGroup
-	mux, err := nl.NewMux()
-	if err != nil {
-		t.Fatal(err)
-	}
-	defer func() {
-		mux.Close()
-		wg.Wait()
-	}()
-	wg.Add(1)
-	go func() {
-		mux.Serve()
-		wg.Done()
-	}()
-
-	conn, err := nl.Open(syscall.NETLINK_GENERIC)
-	if err != nil {
-		t.Fatal(err)
-	}
-	defer conn.Close()
-
-	c, err := NewClient(conn, mux)
+	c, err := Dial()
 	if err != nil {
 		t.Fatal(err)
 	}
+	defer c.Close()
 
 	link, err := GetLink("upfgtp")
 	if err != nil {
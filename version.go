@@ -1,7 +1,11 @@
 package gtp5gnl
 
 import (
+	"context"
 	"fmt"
+	"slices"
+	"strconv"
+	"strings"
 	"syscall"
 
 	"github.com/khirono/go-genl"
@@ -9,17 +13,25 @@ import (
 )
 
 func GetVersion(c *Client) (string, error) {
-	flags := syscall.NLM_F_ACK
-	req := nl.NewRequest(c.ID, flags)
-	err := req.Append(genl.Header{Cmd: CMD_GET_VERSION})
-	if err != nil {
-		return "", err
-	}
+	return GetVersionContext(context.Background(), c)
+}
 
-	rsps, err := c.Do(req)
+func GetVersionContext(ctx context.Context, c *Client) (string, error) {
+	rsps, err := c.DoContext(ctx, versionRequest(c))
 	if err != nil {
 		return "", err
 	}
+	return decodeVersionReply(rsps)
+}
+
+func versionRequest(c *Client) *nl.Request {
+	flags := syscall.NLM_F_ACK
+	req := nl.NewRequest(c.familyID(), flags)
+	req.Append(genl.Header{Cmd: CMD_GET_VERSION})
+	return req
+}
+
+func decodeVersionReply(rsps []nl.Msg) (string, error) {
 	if len(rsps) != 1 {
 		return "", fmt.Errorf("invalid Version")
 	}
@@ -29,3 +41,131 @@ func GetVersion(c *Client) (string, error) {
 	}
 	return ver, err
 }
+
+// Version is the version of the gtp5g module, e.g. 0.9.5.
+type Version struct {
+	Major int
+	Minor int
+	Patch int
+}
+
+// ParseVersion parses versions like "0.9.5", "v0.9" or "0.9.5-rc1"; any
+// suffix after the numbers is ignored.
+func ParseVersion(s string) (Version, error) {
+	var v Version
+	t := strings.TrimPrefix(strings.TrimSpace(s), "v")
+	if i := strings.IndexAny(t, "-+ "); i >= 0 {
+		t = t[:i]
+	}
+	parts := strings.Split(t, ".")
+	if len(parts) > 3 {
+		return v, fmt.Errorf("invalid version %q", s)
+	}
+	fields := []*int{&v.Major, &v.Minor, &v.Patch}
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return Version{}, fmt.Errorf("invalid version %q", s)
+		}
+		*fields[i] = n
+	}
+	return v, nil
+}
+
+func (v Version) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// Compare returns -1, 0 or +1 as v is older than, equal to or newer than w.
+func (v Version) Compare(w Version) int {
+	a := [3]int{v.Major, v.Minor, v.Patch}
+	b := [3]int{w.Major, w.Minor, w.Patch}
+	for i := range a {
+		switch {
+		case a[i] < b[i]:
+			return -1
+		case a[i] > b[i]:
+			return 1
+		}
+	}
+	return 0
+}
+
+func (v Version) AtLeast(major, minor, patch int) bool {
+	return v.Compare(Version{major, minor, patch}) >= 0
+}
+
+// Capabilities tells which optional commands the loaded gtp5g module
+// supports. A zero Version means the version could not be read; nothing is
+// refused then.
+type Capabilities struct {
+	Version        Version
+	MultiReports   bool // CMD_GET_MULTI_REPORTS
+	UsageStatistic bool // CMD_GET_USAGE_STATISTIC
+	BufferGTPU     bool // CMD_BUFFER_GTPU
+}
+
+// capabilitiesOf returns the capabilities of a gtp5g module of version v
+// that registers the commands ops with the generic netlink controller. ops
+// is empty if the controller did not list them; all commands are taken as
+// supported then, and the kernel decides.
+func capabilitiesOf(v Version, ops []genl.Op) Capabilities {
+	has := func(cmd int) bool {
+		return len(ops) == 0 || slices.ContainsFunc(ops, func(op genl.Op) bool {
+			return op.ID == uint32(cmd)
+		})
+	}
+	return Capabilities{
+		Version:        v,
+		MultiReports:   has(CMD_GET_MULTI_REPORTS),
+		UsageStatistic: has(CMD_GET_USAGE_STATISTIC),
+		BufferGTPU:     has(CMD_BUFFER_GTPU),
+	}
+}
+
+// Capabilities returns the capabilities of the gtp5g module, probed when c
+// was created or the module was reloaded.
+func (c *Client) Capabilities() Capabilities {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.caps
+}
+
+func (c *Client) setCaps(caps Capabilities) {
+	c.mu.Lock()
+	c.caps = caps
+	c.mu.Unlock()
+}
+
+// probe reads the module version into c.caps. It sends the request
+// directly, so the caller must own c.sem or not yet share c.
+func (c *Client) probe() {
+	c.setCaps(Capabilities{})
+	rsps, err := c.do(context.Background(), versionRequest(c))
+	if err != nil {
+		return
+	}
+	s, err := decodeVersionReply(rsps)
+	if err != nil {
+		return
+	}
+	v, err := ParseVersion(s)
+	if err != nil {
+		return
+	}
+	c.mu.RLock()
+	caps := capabilitiesOf(v, c.ops)
+	c.mu.RUnlock()
+	c.setCaps(caps)
+}
+
+// require fails with an *UnsupportedError if the module is known not to
+// support feature. The check is skipped if the version of the module could
+// not be read: the request is sent and the kernel decides.
+func (c *Client) require(feature string, supported bool) error {
+	v := c.Capabilities().Version
+	if supported || v == (Version{}) {
+		return nil
+	}
+	return &UnsupportedError{Feature: feature, Version: v}
+}
@@ -0,0 +1,108 @@
+package gtp5gnl
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/khirono/go-genl"
+)
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		s    string
+		want Version
+	}{
+		{"0.9.5", Version{0, 9, 5}},
+		{"v0.8.10", Version{0, 8, 10}},
+		{"0.9", Version{0, 9, 0}},
+		{"1.0.0-rc1", Version{1, 0, 0}},
+		{" 0.9.1\n", Version{0, 9, 1}},
+	}
+	for _, tt := range tests {
+		got, err := ParseVersion(tt.s)
+		if err != nil {
+			t.Errorf("ParseVersion(%q): %v", tt.s, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseVersion(%q) = %v, want %v", tt.s, got, tt.want)
+		}
+	}
+	for _, s := range []string{"", "x.y", "0.9.5.1", "0.-1"} {
+		_, err := ParseVersion(s)
+		if err == nil {
+			t.Errorf("ParseVersion(%q): want error", s)
+		}
+	}
+}
+
+func TestVersionCompare(t *testing.T) {
+	v := Version{0, 8, 6}
+	if !v.AtLeast(0, 8, 6) || !v.AtLeast(0, 8, 0) || v.AtLeast(0, 9, 0) {
+		t.Errorf("unexpected AtLeast results for %v", v)
+	}
+	if v.Compare(Version{1, 0, 0}) != -1 || v.Compare(Version{0, 8, 1}) != 1 {
+		t.Errorf("unexpected Compare results for %v", v)
+	}
+}
+
+func TestCapabilitiesRequire(t *testing.T) {
+	ops := []genl.Op{{ID: CMD_ADD_PDR}, {ID: CMD_GET_REPORT}}
+	c := &Client{sem: make(chan struct{}, 1), caps: capabilitiesOf(Version{0, 7, 2}, ops)}
+	link := &Link{Name: "upfgtp", Index: 1}
+
+	_, err := GetMultiReportsOID(c, link, []OID{{1, 1}})
+	if !errors.Is(err, errors.ErrUnsupported) {
+		t.Fatalf("want ErrUnsupported, got %v", err)
+	}
+	want := "CMD_GET_MULTI_REPORTS unsupported by gtp5g v0.7"
+	if err.Error() != want {
+		t.Errorf("got %q, want %q", err, want)
+	}
+
+	_, err = GetUsageStatistic(c, link)
+	if !errors.Is(err, errors.ErrUnsupported) {
+		t.Errorf("want ErrUnsupported, got %v", err)
+	}
+
+	err = SendBufferedPacket(c, link, OID{1, 1}, []byte{0x45})
+	if !errors.Is(err, errors.ErrUnsupported) {
+		t.Errorf("want ErrUnsupported, got %v", err)
+	}
+}
+
+func TestCapabilitiesOf(t *testing.T) {
+	caps := capabilitiesOf(Version{0, 9, 0}, []genl.Op{{ID: CMD_ADD_PDR}, {ID: CMD_GET_USAGE_STATISTIC}})
+	if caps.MultiReports || !caps.UsageStatistic || caps.BufferGTPU {
+		t.Errorf("want only UsageStatistic, got %+v", caps)
+	}
+
+	caps = capabilitiesOf(Version{0, 7, 2}, nil)
+	if !caps.MultiReports || !caps.UsageStatistic || !caps.BufferGTPU {
+		t.Errorf("want all commands without ops, got %+v", caps)
+	}
+}
+
+func TestGetVersion(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping testing in short mode")
+	}
+
+	c, err := Dial()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	s, err := GetVersion(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err := ParseVersion(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Capabilities().Version != v {
+		t.Errorf("capabilities of %v, want %v", c.Capabilities().Version, v)
+	}
+}